@@ -8,11 +8,14 @@ var (
 	// Tenant errors
 	ErrTenantNotFound                  = errors.New("tenant not found")
 	ErrTenantUniqueConstraintViolation = errors.New("tenant unique constraint violation")
+	ErrTenantIDEmpty                   = errors.New("tenant id is empty")
+	ErrInvalidCompactionTime           = errors.New("invalid last compaction time")
 
 	// Database errors
 	ErrDatabaseNotFound                  = errors.New("database not found")
 	ErrDatabaseUniqueConstraintViolation = errors.New("database unique constraint violation")
 	ErrDatabaseNameEmpty                 = errors.New("database name is empty")
+	ErrQuotaExceeded                     = errors.New("quota exceeded")
 
 	// Collection errors
 	ErrCollectionNotFound                    = errors.New("collection not found")
@@ -23,15 +26,36 @@ var (
 	ErrCollectionLogPositionStale            = errors.New("collection log position Stale")
 	ErrCollectionVersionStale                = errors.New("collection version stale")
 	ErrCollectionVersionInvalid              = errors.New("collection version invalid")
+	ErrCollectionRequestNil                  = errors.New("collection request is nil")
+
+	// Collection validation errors
+	ErrInvalidName           = errors.New("invalid name")
+	ErrInvalidDimension      = errors.New("invalid collection dimension")
+	ErrVersionConflict       = errors.New("collection version conflict")
+	ErrLogPositionRegression = errors.New("collection log position regression")
+	ErrDatabaseMismatch      = errors.New("collection is not in the expected database")
+	ErrTenantMismatch        = errors.New("collection is not in the expected tenant")
+	ErrSameDatabase          = errors.New("source and target database are the same")
+	ErrInvalidLabel          = errors.New("invalid collection label")
+	ErrInvalidFlush          = errors.New("invalid flush compaction")
+	ErrReadOnly              = errors.New("collection is read only")
+	ErrActorEmpty            = errors.New("actor is empty")
+	ErrNameConflict          = errors.New("collection name already in use")
+	ErrInvalidTimeWindow     = errors.New("invalid time window")
 
 	// Collection metadata errors
 	ErrUnknownCollectionMetadataType = errors.New("collection metadata value type not supported")
 	ErrInvalidMetadataUpdate         = errors.New("invalid metadata update, reest metadata true and metadata value not empty")
+	ErrMetadataTooLarge              = errors.New("collection metadata too large")
+	ErrMalformedMetadataJSON         = errors.New("collection metadata is not a valid JSON object")
+	ErrMetadataTypeChanged           = errors.New("collection metadata value type changed")
 
 	// Segment errors
 	ErrSegmentIDFormat                  = errors.New("segment id format error")
 	ErrInvalidCollectionUpdate          = errors.New("invalid collection update, reset collection true and collection value not empty")
 	ErrMissingCollectionID              = errors.New("missing collection id")
+	ErrMissingSegmentID                 = errors.New("missing segment id")
+	ErrUnknownSegmentScope              = errors.New("unknown segment scope")
 	ErrSegmentUniqueConstraintViolation = errors.New("unique constraint violation")
 	ErrSegmentDeleteNonExistingSegment  = errors.New("delete non existing segment")
 	ErrSegmentUpdateNonExistingSegment  = errors.New("update non existing segment")
@@ -1,6 +1,11 @@
 package model
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/chroma-core/chroma/go/pkg/common"
 	"github.com/chroma-core/chroma/go/pkg/types"
 )
 
@@ -8,51 +13,903 @@ type Collection struct {
 	ID                   types.UniqueID
 	Name                 string
 	ConfigurationJsonStr string
+	Configuration        *CollectionConfiguration
+	DistanceFunction     *string
+	EmbeddingFunction    *string
 	Dimension            *int32
 	Metadata             *CollectionMetadata[CollectionMetadataValueType]
+	Labels               map[string]string
 	TenantID             string
 	DatabaseName         string
 	Ts                   types.Timestamp
 	LogPosition          int64
+	LastCompactionTime   int64
 	Version              int32
+	UpdateVersion        int32
+	DeletedAt            *types.Timestamp
+	ExpiresAt            *types.Timestamp
+	State                CollectionState
+	SourceCollectionID   *types.UniqueID
+	ForkedAt             *types.Timestamp
+	ReadOnly             bool
+	Stats                *CollectionStats
+	CreatedBy            string
+	UpdatedBy            string
+	RequiresReindex      bool
+}
+
+// Clone returns a deep copy of the collection that shares no mutable state
+// with the original.
+func (c *Collection) Clone() *Collection {
+	if c == nil {
+		return nil
+	}
+	clone := *c
+	clone.Configuration = c.Configuration.Clone()
+	clone.DistanceFunction = cloneString(c.DistanceFunction)
+	clone.EmbeddingFunction = cloneString(c.EmbeddingFunction)
+	clone.Dimension = cloneInt32(c.Dimension)
+	clone.DeletedAt = cloneTimestamp(c.DeletedAt)
+	clone.ExpiresAt = cloneTimestamp(c.ExpiresAt)
+	clone.Metadata = c.Metadata.Clone()
+	clone.Labels = cloneLabels(c.Labels)
+	clone.SourceCollectionID = cloneUniqueID(c.SourceCollectionID)
+	clone.ForkedAt = cloneTimestamp(c.ForkedAt)
+	clone.Stats = c.Stats.Clone()
+	return &clone
+}
+
+// Equal reports whether c and other describe the same collection. Pointer
+// fields are compared by value and metadata key by key, so two collections
+// built independently compare equal. Two nil collections are equal.
+func (c *Collection) Equal(other *Collection) bool {
+	if c == nil || other == nil {
+		return c == other
+	}
+	return c.ID == other.ID &&
+		c.Name == other.Name &&
+		c.ConfigurationJsonStr == other.ConfigurationJsonStr &&
+		c.Configuration.Equal(other.Configuration) &&
+		equalPtr(c.DistanceFunction, other.DistanceFunction) &&
+		equalPtr(c.EmbeddingFunction, other.EmbeddingFunction) &&
+		equalPtr(c.Dimension, other.Dimension) &&
+		c.Metadata.Equals(other.Metadata) &&
+		equalLabels(c.Labels, other.Labels) &&
+		c.TenantID == other.TenantID &&
+		c.DatabaseName == other.DatabaseName &&
+		c.Ts == other.Ts &&
+		c.LogPosition == other.LogPosition &&
+		c.LastCompactionTime == other.LastCompactionTime &&
+		c.Version == other.Version &&
+		c.UpdateVersion == other.UpdateVersion &&
+		equalPtr(c.DeletedAt, other.DeletedAt) &&
+		equalPtr(c.ExpiresAt, other.ExpiresAt) &&
+		c.State == other.State &&
+		equalPtr(c.SourceCollectionID, other.SourceCollectionID) &&
+		equalPtr(c.ForkedAt, other.ForkedAt) &&
+		c.ReadOnly == other.ReadOnly &&
+		equalPtr(c.Stats, other.Stats) &&
+		c.CreatedBy == other.CreatedBy &&
+		c.UpdatedBy == other.UpdatedBy &&
+		c.RequiresReindex == other.RequiresReindex
+}
+
+// EffectiveDistanceFunction returns the collection's distance function in
+// lowercase, or DefaultDistanceFunction when none is set.
+func (c *Collection) EffectiveDistanceFunction() string {
+	if c.DistanceFunction == nil {
+		return DefaultDistanceFunction
+	}
+	return strings.ToLower(*c.DistanceFunction)
+}
+
+// HasDimension reports whether the collection has a usable dimension. A nil
+// dimension is deferred until the first insert; zero or negative is never
+// valid and is treated as unset.
+func (c *Collection) HasDimension() bool {
+	return c.Dimension != nil && *c.Dimension > 0
+}
+
+// DimensionValue returns the collection's dimension and whether HasDimension
+// holds. The dimension is 0 when it does not.
+func (c *Collection) DimensionValue() (int32, bool) {
+	if !c.HasDimension() {
+		return 0, false
+	}
+	return *c.Dimension, true
+}
+
+// IsExpired reports whether the collection's ExpiresAt has been reached. A
+// collection without ExpiresAt never expires.
+func (c *Collection) IsExpired(now types.Timestamp) bool {
+	return c.ExpiresAt != nil && now >= *c.ExpiresAt
+}
+
+// CompactionAge returns how long ago, relative to now, the collection was last
+// compacted, in the units of LastCompactionTime. It is 0 for a collection that
+// was never compacted and when clock skew puts LastCompactionTime after now.
+func (c *Collection) CompactionAge(now int64) int64 {
+	if c.LastCompactionTime == 0 || now <= c.LastCompactionTime {
+		return 0
+	}
+	return now - c.LastCompactionTime
+}
+
+// AgeSince returns the time elapsed from the collection's Ts to now. It is 0
+// for a collection without a Ts and when clock skew puts Ts after now.
+func (c *Collection) AgeSince(now types.Timestamp) types.Timestamp {
+	if c.Ts == 0 || now <= c.Ts {
+		return 0
+	}
+	return now - c.Ts
+}
+
+// IdleSince returns how long the collection has gone without a compaction. It
+// is CompactionAge under the name retention predicates use.
+func (c *Collection) IdleSince(now int64) int64 {
+	return c.CompactionAge(now)
+}
+
+const (
+	scopeFieldTenantID     = "tenant_id"
+	scopeFieldDatabaseName = "database_name"
+)
+
+// validateScope rejects an empty or whitespace-only tenant or database with a
+// *MissingScopeError per missing field. Every mutation that writes a
+// collection row must call it.
+func validateScope(tenantID, databaseName string) error {
+	var violations []error
+	if strings.TrimSpace(tenantID) == "" {
+		violations = append(violations, &MissingScopeError{Field: scopeFieldTenantID})
+	}
+	if strings.TrimSpace(databaseName) == "" {
+		violations = append(violations, &MissingScopeError{Field: scopeFieldDatabaseName})
+	}
+	return newValidationError(violations)
 }
 
 type CreateCollection struct {
 	ID                   types.UniqueID
 	Name                 string
 	ConfigurationJsonStr string
+	Configuration        *CollectionConfiguration
+	DistanceFunction     *string
+	EmbeddingFunction    *string
 	Dimension            *int32
 	Metadata             *CollectionMetadata[CollectionMetadataValueType]
+	Labels               map[string]string
 	GetOrCreate          bool
+	IdempotencyKey       *string
 	TenantID             string
 	DatabaseName         string
 	Ts                   types.Timestamp
+	ExpiresAt            *types.Timestamp
+	CreatedBy            string
+	RequireActor         bool
+}
+
+// Validate reports every problem with the request as a single
+// *ValidationError. With RequireActor set, CreatedBy must not be empty.
+func (c *CreateCollection) Validate() error {
+	var violations []error
+	if c.ID == types.NilUniqueID() {
+		violations = append(violations, common.ErrMissingCollectionID)
+	}
+	if c.Name == "" {
+		violations = append(violations, common.ErrCollectionNameEmpty)
+	} else if _, err := NormalizeAndValidateName(c.Name); err != nil {
+		violations = append(violations, err)
+	}
+	if err := validateScope(c.TenantID, c.DatabaseName); err != nil {
+		violations = append(violations, err)
+	}
+	if c.Dimension != nil && *c.Dimension <= 0 {
+		violations = append(violations, &InvalidDimensionError{Dimension: *c.Dimension})
+	}
+	if c.ExpiresAt != nil && *c.ExpiresAt < c.Ts {
+		violations = append(violations, &InvalidExpiryError{ExpiresAt: *c.ExpiresAt, Ts: c.Ts})
+	}
+	if err := c.Configuration.Validate(); err != nil {
+		violations = append(violations, err)
+	}
+	if c.DistanceFunction != nil {
+		if _, err := NormalizeDistanceFunction(*c.DistanceFunction); err != nil {
+			violations = append(violations, err)
+		}
+	}
+	if err := ValidateEmbeddingFunction(c.EmbeddingFunction); err != nil {
+		violations = append(violations, err)
+	}
+	if err := ValidateMetadata(c.Metadata); err != nil {
+		violations = append(violations, err)
+	}
+	if err := validateUserMetadata(c.Metadata); err != nil {
+		violations = append(violations, err)
+	}
+	if err := ValidateLabels(c.Labels); err != nil {
+		violations = append(violations, err)
+	}
+	if c.RequireActor && c.CreatedBy == "" {
+		violations = append(violations, common.ErrActorEmpty)
+	}
+	return newValidationError(violations)
+}
+
+// ApplyDefaults adds the default metadata keys that the request does not
+// already set; client-supplied values are never overwritten. Defaults are
+// user metadata, so reserved keys are rejected and nothing is applied.
+func (c *CreateCollection) ApplyDefaults(defaults map[string]CollectionMetadataValueType) error {
+	if len(defaults) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(defaults))
+	for key := range defaults {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if IsReservedKey(key) {
+			return &ReservedMetadataKeyError{Key: key}
+		}
+	}
+	if c.Metadata == nil {
+		c.Metadata = NewCollectionMetadata[CollectionMetadataValueType]()
+	}
+	for _, key := range keys {
+		if _, ok := c.Metadata.Metadata[key]; !ok {
+			c.Metadata.Add(key, cloneCollectionMetadataValue(defaults[key]))
+		}
+	}
+	return nil
+}
+
+// IdempotentResult returns the result of retrying c when existing was
+// created by a request with the same IdempotencyKey in the same tenant and
+// database: the create is a no-op returning existing. It returns nil when the
+// request has no idempotency key or existing does not match it.
+func (c *CreateCollection) IdempotentResult(existing *Collection) *CreateCollectionResult {
+	if c.IdempotencyKey == nil || existing == nil {
+		return nil
+	}
+	if existing.TenantID != c.TenantID || existing.DatabaseName != c.DatabaseName {
+		return nil
+	}
+	if !MatchesIdempotencyKey(existing, *c.IdempotencyKey) {
+		return nil
+	}
+	return NewCreateCollectionResult(existing, false)
+}
+
+// MatchesIdempotencyKey reports whether existing was stamped with key by the
+// create that made it. An empty key never matches.
+func MatchesIdempotencyKey(existing *Collection, key string) bool {
+	if existing == nil || key == "" {
+		return false
+	}
+	stamped, ok := existing.Metadata.GetString(IdempotencyKeyMetadataKey)
+	return ok && stamped == key
+}
+
+// NewCollectionFromCreate builds the collection a CreateCollection describes.
+// The creator is recorded as both CreatedBy and UpdatedBy, the distance
+// function and configuration space are stored in lowercase, and an
+// IdempotencyKey is stamped into the reserved IdempotencyKeyMetadataKey.
+func NewCollectionFromCreate(create *CreateCollection) *Collection {
+	collection := &Collection{
+		ID:                   create.ID,
+		Name:                 create.Name,
+		ConfigurationJsonStr: create.ConfigurationJsonStr,
+		Configuration:        create.Configuration.normalized(),
+		DistanceFunction:     normalizeSpace(create.DistanceFunction),
+		EmbeddingFunction:    cloneString(create.EmbeddingFunction),
+		Dimension:            cloneInt32(create.Dimension),
+		Metadata:             create.Metadata.Clone(),
+		Labels:               cloneLabels(create.Labels),
+		TenantID:             create.TenantID,
+		DatabaseName:         create.DatabaseName,
+		Ts:                   create.Ts,
+		ExpiresAt:            cloneTimestamp(create.ExpiresAt),
+		CreatedBy:            create.CreatedBy,
+		UpdatedBy:            create.CreatedBy,
+	}
+	if create.IdempotencyKey != nil {
+		collection.Metadata = SetReservedMetadata(collection.Metadata, IdempotencyKeyMetadataKey, &CollectionMetadataValueStringType{Value: *create.IdempotencyKey})
+	}
+	return collection
+}
+
+// CreateCollectionResult is the outcome of a CreateCollection. When
+// GetOrCreate matches an existing collection the coordinator returns that
+// collection with Created set to false.
+type CreateCollectionResult struct {
+	Collection *Collection
+	Created    bool
+}
+
+func NewCreateCollectionResult(c *Collection, created bool) *CreateCollectionResult {
+	return &CreateCollectionResult{
+		Collection: c,
+		Created:    created,
+	}
 }
 
 type DeleteCollection struct {
+	ID                    types.UniqueID
+	TenantID              string
+	DatabaseName          string
+	Ts                    types.Timestamp
+	SoftDelete            bool
+	AllowReadOnlyOverride bool
+}
+
+func (d *DeleteCollection) Validate() error {
+	var violations []error
+	if d.ID == types.NilUniqueID() {
+		violations = append(violations, common.ErrMissingCollectionID)
+	}
+	if err := validateScope(d.TenantID, d.DatabaseName); err != nil {
+		violations = append(violations, err)
+	}
+	return newValidationError(violations)
+}
+
+// CheckReadOnly rejects deleting a read only collection unless
+// AllowReadOnlyOverride is set.
+func (d *DeleteCollection) CheckReadOnly(existing *Collection) error {
+	if existing != nil && existing.ReadOnly && !d.AllowReadOnlyOverride {
+		return &ReadOnlyError{CollectionID: existing.ID}
+	}
+	return nil
+}
+
+// MoveCollection relocates a collection to another database of the same
+// tenant without recreating it.
+type MoveCollection struct {
 	ID           types.UniqueID
 	TenantID     string
+	FromDatabase string
+	ToDatabase   string
+	Ts           types.Timestamp
+}
+
+func (m *MoveCollection) Validate() error {
+	var violations []error
+	if m.ID == types.NilUniqueID() {
+		violations = append(violations, common.ErrMissingCollectionID)
+	}
+	if m.TenantID == "" {
+		violations = append(violations, common.ErrTenantIDEmpty)
+	}
+	if m.FromDatabase == "" || m.ToDatabase == "" {
+		violations = append(violations, common.ErrDatabaseNameEmpty)
+	} else if m.FromDatabase == m.ToDatabase {
+		violations = append(violations, common.ErrSameDatabase)
+	}
+	return newValidationError(violations)
+}
+
+// Apply moves c to ToDatabase. c must be the collection named by ID and
+// currently be in TenantID and FromDatabase; it is left unchanged when the
+// move is rejected.
+func (m *MoveCollection) Apply(c *Collection) error {
+	if err := m.Validate(); err != nil {
+		return err
+	}
+	if c == nil {
+		return common.ErrCollectionNotFound
+	}
+	if c.ID != m.ID {
+		return &CollectionIDMismatchError{Expected: m.ID, Actual: c.ID}
+	}
+	if c.TenantID != m.TenantID {
+		return &TenantMismatchError{CollectionID: c.ID, Expected: m.TenantID, Actual: c.TenantID}
+	}
+	if c.DatabaseName != m.FromDatabase {
+		return &DatabaseMismatchError{CollectionID: c.ID, Expected: m.FromDatabase, Actual: c.DatabaseName}
+	}
+	c.DatabaseName = m.ToDatabase
+	return nil
+}
+
+// ForkCollection creates a new collection from an existing one, copying its
+// schema and metadata and recording where it came from.
+type ForkCollection struct {
+	SourceID     types.UniqueID
+	NewID        types.UniqueID
+	NewName      string
+	TenantID     string
 	DatabaseName string
 	Ts           types.Timestamp
 }
 
+func (f *ForkCollection) Validate() error {
+	var violations []error
+	if f.SourceID == types.NilUniqueID() || f.NewID == types.NilUniqueID() {
+		violations = append(violations, common.ErrMissingCollectionID)
+	}
+	if f.NewName == "" {
+		violations = append(violations, common.ErrCollectionNameEmpty)
+	} else if _, err := NormalizeAndValidateName(f.NewName); err != nil {
+		violations = append(violations, err)
+	}
+	if f.TenantID == "" {
+		violations = append(violations, common.ErrTenantIDEmpty)
+	}
+	if f.DatabaseName == "" {
+		violations = append(violations, common.ErrDatabaseNameEmpty)
+	}
+	return newValidationError(violations)
+}
+
+// Apply returns the forked collection. The fork starts with an empty log and
+// version, and is rejected if it would take its source's name in the same
+// database. Like a copy, the fork does not inherit the source's
+// IdempotencyKeyMetadataKey.
+func (f *ForkCollection) Apply(source *Collection) (*Collection, error) {
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+	if source == nil || source.ID != f.SourceID {
+		return nil, common.ErrCollectionNotFound
+	}
+	name, _ := NormalizeAndValidateName(f.NewName)
+	if name == source.Name && f.TenantID == source.TenantID && f.DatabaseName == source.DatabaseName {
+		return nil, common.ErrCollectionUniqueConstraintViolation
+	}
+	sourceID := source.ID
+	forkedAt := f.Ts
+	return &Collection{
+		ID:                   f.NewID,
+		Name:                 name,
+		ConfigurationJsonStr: source.ConfigurationJsonStr,
+		Configuration:        source.Configuration.Clone(),
+		DistanceFunction:     cloneString(source.DistanceFunction),
+		EmbeddingFunction:    cloneString(source.EmbeddingFunction),
+		Dimension:            cloneInt32(source.Dimension),
+		Metadata:             inheritMetadata(source.Metadata),
+		Labels:               cloneLabels(source.Labels),
+		TenantID:             f.TenantID,
+		DatabaseName:         f.DatabaseName,
+		Ts:                   f.Ts,
+		SourceCollectionID:   &sourceID,
+		ForkedAt:             &forkedAt,
+	}, nil
+}
+
+// CopyCollection creates a new collection with the configuration of an
+// existing one and, with IncludeMetadata, a copy of its metadata. Unlike a
+// fork, the copy records no link to its source.
+type CopyCollection struct {
+	SourceID        types.UniqueID
+	NewID           types.UniqueID
+	NewName         string
+	TenantID        string
+	DatabaseName    string
+	IncludeMetadata bool
+	Ts              types.Timestamp
+}
+
+func (c *CopyCollection) Validate() error {
+	var violations []error
+	if c.SourceID == types.NilUniqueID() || c.NewID == types.NilUniqueID() {
+		violations = append(violations, common.ErrMissingCollectionID)
+	}
+	if c.NewName == "" {
+		violations = append(violations, common.ErrCollectionNameEmpty)
+	} else if _, err := NormalizeAndValidateName(c.NewName); err != nil {
+		violations = append(violations, err)
+	}
+	if err := validateScope(c.TenantID, c.DatabaseName); err != nil {
+		violations = append(violations, err)
+	}
+	return newValidationError(violations)
+}
+
+// Apply returns the copied collection. The copy starts with an empty log and
+// version and no lineage, and is rejected with a *NameConflictError if it
+// would take its source's name in the same database. Copied metadata drops
+// the source's IdempotencyKeyMetadataKey, which identifies the request that
+// created the source.
+func (c *CopyCollection) Apply(source *Collection) (*Collection, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	if source == nil || source.ID != c.SourceID {
+		return nil, common.ErrCollectionNotFound
+	}
+	name, _ := NormalizeAndValidateName(c.NewName)
+	if err := CheckNameUnique([]*Collection{source}, name, c.TenantID, c.DatabaseName, c.NewID); err != nil {
+		return nil, err
+	}
+	copied := &Collection{
+		ID:                   c.NewID,
+		Name:                 name,
+		ConfigurationJsonStr: source.ConfigurationJsonStr,
+		Configuration:        source.Configuration.Clone(),
+		DistanceFunction:     cloneString(source.DistanceFunction),
+		EmbeddingFunction:    cloneString(source.EmbeddingFunction),
+		Dimension:            cloneInt32(source.Dimension),
+		TenantID:             c.TenantID,
+		DatabaseName:         c.DatabaseName,
+		Ts:                   c.Ts,
+	}
+	if c.IncludeMetadata {
+		copied.Metadata = inheritMetadata(source.Metadata)
+	}
+	return copied, nil
+}
+
+// inheritMetadata clones a source collection's metadata for a collection
+// derived from it, dropping the IdempotencyKeyMetadataKey that identifies the
+// request that created the source. Metadata left empty becomes nil.
+func inheritMetadata(m *CollectionMetadata[CollectionMetadataValueType]) *CollectionMetadata[CollectionMetadataValueType] {
+	if m == nil {
+		return nil
+	}
+	inherited := m.Clone()
+	inherited.Remove(IdempotencyKeyMetadataKey)
+	if inherited.Empty() {
+		return nil
+	}
+	return inherited
+}
+
 type UpdateCollection struct {
-	ID            types.UniqueID
-	Name          *string
-	Dimension     *int32
-	Metadata      *CollectionMetadata[CollectionMetadataValueType]
-	ResetMetadata bool
-	TenantID      string
-	DatabaseName  string
-	Ts            types.Timestamp
+	ID                           types.UniqueID
+	Name                         *string
+	Configuration                *CollectionConfiguration
+	Dimension                    *int32
+	Metadata                     *CollectionMetadata[CollectionMetadataValueType]
+	ResetMetadata                bool
+	Labels                       map[string]string
+	ReadOnly                     *bool
+	ReindexOnDimensionChange     bool
+	AllowReadOnlyOverride        bool
+	ExpectedVersion              *int32
+	EnforceMetadataTypeStability bool
+	UpdatedBy                    string
+	RequireActor                 bool
+	TenantID                     string
+	DatabaseName                 string
+	Ts                           types.Timestamp
+}
+
+// Validate checks the update against the collection it will be applied to
+// and reports every problem as a single *ValidationError. A dimension must be
+// positive; it may be set on a collection that has none, but only changed
+// with ReindexOnDimensionChange. A read only
+// collection only accepts updates that toggle ReadOnly, unless
+// AllowReadOnlyOverride is set. With RequireActor set, UpdatedBy must not be
+// empty. With EnforceMetadataTypeStability set, an existing metadata key may
+// only be given a value of the same kind; new keys and deletions are allowed.
+// An unset TenantID or DatabaseName defaults to existing's; a set one must
+// match it, as an update never moves a collection.
+func (u *UpdateCollection) Validate(existing *Collection) error {
+	var violations []error
+	if existing != nil && existing.ReadOnly && !u.AllowReadOnlyOverride && !u.onlyTogglesReadOnly() {
+		violations = append(violations, &ReadOnlyError{CollectionID: existing.ID})
+	}
+	if u.Name != nil {
+		if _, err := NormalizeAndValidateName(*u.Name); err != nil {
+			violations = append(violations, err)
+		}
+	}
+	if u.Dimension != nil && *u.Dimension <= 0 {
+		violations = append(violations, &InvalidDimensionError{Dimension: *u.Dimension})
+	} else if u.changesDimension(existing) && !u.ReindexOnDimensionChange {
+		violations = append(violations, &DimensionMismatchError{Existing: *existing.Dimension, Requested: *u.Dimension})
+	}
+	if err := u.Configuration.Validate(); err != nil {
+		violations = append(violations, err)
+	}
+	if u.Metadata != nil {
+		if err := ValidateMetadata(ApplyMetadataUpdate(existing, u)); err != nil {
+			violations = append(violations, err)
+		}
+		if err := validateUserMetadata(u.Metadata); err != nil {
+			violations = append(violations, err)
+		}
+		if u.EnforceMetadataTypeStability {
+			violations = append(violations, u.metadataTypeChanges(existing)...)
+		}
+	}
+	if err := ValidateLabels(u.Labels); err != nil {
+		violations = append(violations, err)
+	}
+	if u.RequireActor && u.UpdatedBy == "" {
+		violations = append(violations, common.ErrActorEmpty)
+	}
+	if err := validateScope(u.scope(existing)); err != nil {
+		violations = append(violations, err)
+	}
+	if existing != nil {
+		if strings.TrimSpace(u.TenantID) != "" && u.TenantID != existing.TenantID {
+			violations = append(violations, &TenantMismatchError{CollectionID: existing.ID, Expected: u.TenantID, Actual: existing.TenantID})
+		}
+		if strings.TrimSpace(u.DatabaseName) != "" && u.DatabaseName != existing.DatabaseName {
+			violations = append(violations, &DatabaseMismatchError{CollectionID: existing.ID, Expected: u.DatabaseName, Actual: existing.DatabaseName})
+		}
+	}
+	return newValidationError(violations)
+}
+
+// scope returns the tenant and database the update applies to. Update
+// requests from the API carry no scope, so an unset TenantID or DatabaseName
+// is taken from existing; the scope is only missing when neither has it.
+func (u *UpdateCollection) scope(existing *Collection) (tenantID, databaseName string) {
+	tenantID, databaseName = u.TenantID, u.DatabaseName
+	if existing != nil {
+		if strings.TrimSpace(tenantID) == "" {
+			tenantID = existing.TenantID
+		}
+		if strings.TrimSpace(databaseName) == "" {
+			databaseName = existing.DatabaseName
+		}
+	}
+	return tenantID, databaseName
+}
+
+// ValidateAll runs every check Validate does and, when the update renames the
+// collection, checks the new name against siblings with CheckNameUnique. All
+// failures are reported together as a single *ValidationError, so an update
+// is either accepted or rejected as a whole.
+func (u *UpdateCollection) ValidateAll(existing *Collection, siblings []*Collection) error {
+	err := u.Validate(existing)
+	if u.Name != nil {
+		tenantID, databaseName := u.scope(existing)
+		if conflict := CheckNameUnique(siblings, *u.Name, tenantID, databaseName, u.ID); conflict != nil {
+			err = appendViolation(err, conflict)
+		}
+	}
+	return err
+}
+
+// ApplyActor records actor as the last modifier of c. CreatedBy is never
+// changed after creation.
+func ApplyActor(c *Collection, actor string) {
+	c.UpdatedBy = actor
+}
+
+// Apply validates the update against existing and returns the updated
+// collection, leaving existing unchanged. A permitted dimension change marks
+// the result as RequiresReindex.
+func (u *UpdateCollection) Apply(existing *Collection) (*Collection, error) {
+	if err := u.Validate(existing); err != nil {
+		return nil, err
+	}
+	updated := existing.Clone()
+	if u.Name != nil {
+		updated.Name, _ = NormalizeAndValidateName(*u.Name)
+	}
+	if u.Configuration != nil {
+		updated.Configuration = u.Configuration.normalized()
+	}
+	if u.changesDimension(existing) {
+		updated.RequiresReindex = true
+	}
+	if u.Dimension != nil {
+		updated.Dimension = cloneInt32(u.Dimension)
+	}
+	if u.Metadata != nil || u.ResetMetadata {
+		updated.Metadata = ApplyMetadataUpdate(existing, u).Clone()
+	}
+	if u.Labels != nil {
+		updated.Labels = cloneLabels(u.Labels)
+	}
+	if u.ReadOnly != nil {
+		updated.ReadOnly = *u.ReadOnly
+	}
+	if u.UpdatedBy != "" {
+		ApplyActor(updated, u.UpdatedBy)
+	}
+	return updated, nil
+}
+
+func (u *UpdateCollection) changesDimension(existing *Collection) bool {
+	return u.Dimension != nil && existing != nil && existing.Dimension != nil && *u.Dimension != *existing.Dimension
+}
+
+func (u *UpdateCollection) metadataTypeChanges(existing *Collection) []error {
+	if existing == nil || existing.Metadata == nil {
+		return nil
+	}
+	var violations []error
+	for _, key := range u.Metadata.SortedKeys() {
+		value := u.Metadata.Metadata[key]
+		if _, ok := value.(*CollectionMetadataValueDeleteType); ok {
+			continue
+		}
+		current, ok := existing.Metadata.Metadata[key]
+		if !ok || current == nil || value == nil {
+			continue
+		}
+		if current.Kind() != value.Kind() {
+			violations = append(violations, &MetadataTypeChangeError{Key: key, Existing: current.Kind(), Requested: value.Kind()})
+		}
+	}
+	return violations
+}
+
+func (u *UpdateCollection) onlyTogglesReadOnly() bool {
+	return u.Name == nil && u.Configuration == nil && u.Dimension == nil && u.Metadata == nil && !u.ResetMetadata && u.Labels == nil
+}
+
+// CheckVersion implements optimistic concurrency for updates. Version is the
+// compaction version, so updates are checked against UpdateVersion instead;
+// callers bump UpdateVersion once the check passes. A nil ExpectedVersion
+// skips the check; otherwise a nil existing is common.ErrCollectionNotFound.
+func (u *UpdateCollection) CheckVersion(existing *Collection) error {
+	if u.ExpectedVersion == nil {
+		return nil
+	}
+	if existing == nil {
+		return common.ErrCollectionNotFound
+	}
+	if *u.ExpectedVersion != existing.UpdateVersion {
+		return &VersionConflictError{Expected: *u.ExpectedVersion, Actual: existing.UpdateVersion}
+	}
+	return nil
+}
+
+// ApplyMetadataUpdate computes the metadata that results from applying update
+// to existing. With ResetMetadata the update's metadata replaces the existing
+// metadata, otherwise the update's keys are merged over the existing ones.
+// Keys set to CollectionMetadataValueDeleteType are removed. An empty result
+// is returned as nil.
+func ApplyMetadataUpdate(existing *Collection, update *UpdateCollection) *CollectionMetadata[CollectionMetadataValueType] {
+	result := NewCollectionMetadata[CollectionMetadataValueType]()
+	if !update.ResetMetadata && existing != nil && existing.Metadata != nil {
+		for key, value := range existing.Metadata.Metadata {
+			result.Add(key, value)
+		}
+	}
+	if update.Metadata != nil {
+		for key, value := range update.Metadata.Metadata {
+			if _, ok := value.(*CollectionMetadataValueDeleteType); ok {
+				result.Remove(key)
+				continue
+			}
+			result.Add(key, value)
+		}
+	}
+	if result.Empty() {
+		return nil
+	}
+	return result
+}
+
+// EffectiveMetadata returns the metadata existing would have after u, without
+// applying it: the result is computed by ApplyMetadataUpdate and deep copied,
+// so neither existing nor u is affected by changes to it. ResetMetadata with
+// nil metadata clears the metadata and returns nil.
+func (u *UpdateCollection) EffectiveMetadata(existing *Collection) *CollectionMetadata[CollectionMetadataValueType] {
+	return ApplyMetadataUpdate(existing, u).Clone()
 }
 
 type FlushCollectionCompaction struct {
 	ID                       types.UniqueID
 	TenantID                 string
 	LogPosition              int64
+	PreviousLogPosition      int64
 	CurrentCollectionVersion int32
 	FlushSegmentCompactions  []*FlushSegmentCompaction
+	TotalRecordsCompacted    uint64
+	Attempt                  int32
+}
+
+// Validate checks that the flush is well formed and then checks it against the
+// collection's current log position and version. Re-flushing the current log
+// position is allowed.
+func (f *FlushCollectionCompaction) Validate(currentLogPosition int64, currentVersion int32) error {
+	if err := f.validateFields(); err != nil {
+		return err
+	}
+	if f.LogPosition < currentLogPosition {
+		return &LogPositionRegressionError{Current: currentLogPosition, Requested: f.LogPosition}
+	}
+	if f.CurrentCollectionVersion != currentVersion {
+		return &VersionMismatchError{Current: currentVersion, Requested: f.CurrentCollectionVersion}
+	}
+	return nil
+}
+
+// ConflictStrategy decides what a flush does when the collection version it
+// was computed against is no longer current. The zero value is ConflictAbort.
+type ConflictStrategy int
+
+const (
+	// ConflictAbort rejects the flush with a *VersionMismatchError.
+	ConflictAbort ConflictStrategy = iota
+	// ConflictRetry drops the flush without an error so it can be recomputed
+	// against the current version.
+	ConflictRetry
+	// ConflictTakeHigherVersion lets the flush win when its version is ahead
+	// of the current one.
+	ConflictTakeHigherVersion
+)
+
+// ResolveConflict reports whether the flush may proceed against
+// currentVersion. Without a mismatch it always proceeds; otherwise strategy
+// decides. ConflictTakeHigherVersion rejects a flush that is not ahead with a
+// *VersionMismatchError.
+func (f *FlushCollectionCompaction) ResolveConflict(currentVersion int32, strategy ConflictStrategy) (proceed bool, err error) {
+	if f.CurrentCollectionVersion == currentVersion {
+		return true, nil
+	}
+	mismatch := &VersionMismatchError{Current: currentVersion, Requested: f.CurrentCollectionVersion}
+	switch strategy {
+	case ConflictAbort:
+		return false, mismatch
+	case ConflictRetry:
+		return false, nil
+	case ConflictTakeHigherVersion:
+		if f.CurrentCollectionVersion > currentVersion {
+			return true, nil
+		}
+		return false, mismatch
+	default:
+		return false, &InvalidFlushError{Field: "conflict_strategy", Reason: fmt.Sprintf("unknown strategy %d", strategy)}
+	}
+}
+
+// validateFields checks the flush on its own, without reference to the
+// collection it is applied to.
+func (f *FlushCollectionCompaction) validateFields() error {
+	if f.Attempt < 0 {
+		return &InvalidFlushError{Field: "attempt", Reason: fmt.Sprintf("must not be negative, got %d", f.Attempt)}
+	}
+	if f.PreviousLogPosition > f.LogPosition {
+		return &InvalidFlushError{Field: "previous_log_position", Reason: fmt.Sprintf("%d is ahead of log position %d", f.PreviousLogPosition, f.LogPosition)}
+	}
+	var segmentRecords uint64
+	for _, segment := range f.FlushSegmentCompactions {
+		if segment == nil {
+			continue
+		}
+		if err := segment.Validate(); err != nil {
+			return err
+		}
+		segmentRecords += segment.RecordsCompacted
+	}
+	if segmentRecords != f.TotalRecordsCompacted {
+		return &InvalidFlushError{Field: "total_records_compacted", Reason: fmt.Sprintf("total %d does not match the segment sum %d", f.TotalRecordsCompacted, segmentRecords)}
+	}
+	return nil
+}
+
+// MaxSegmentCompactionTime returns the most recent LastCompactionTime of the
+// flushed segments, or 0 when there are none.
+func (f *FlushCollectionCompaction) MaxSegmentCompactionTime() int64 {
+	var max int64
+	for _, segment := range f.FlushSegmentCompactions {
+		if segment != nil && segment.LastCompactionTime > max {
+			max = segment.LastCompactionTime
+		}
+	}
+	return max
+}
+
+// NextAttempt returns a copy of the flush for a retry, with Attempt
+// incremented. The copy shares its segment compactions with f.
+func (f *FlushCollectionCompaction) NextAttempt() FlushCollectionCompaction {
+	next := *f
+	next.Attempt++
+	return next
+}
+
+// Rollback returns a copy of the flush with LogPosition reverted to
+// PreviousLogPosition, for use when the flush fails downstream. The version
+// is left unchanged.
+func (f *FlushCollectionCompaction) Rollback() FlushCollectionCompaction {
+	rolledBack := *f
+	rolledBack.LogPosition = f.PreviousLogPosition
+	return rolledBack
+}
+
+// ShouldGiveUp reports whether the flush has used up maxAttempts attempts.
+func (f *FlushCollectionCompaction) ShouldGiveUp(maxAttempts int32) bool {
+	return f.Attempt >= maxAttempts
 }
 
 type FlushCollectionInfo struct {
@@ -61,12 +918,140 @@ type FlushCollectionInfo struct {
 	TenantLastCompactionTime int64
 }
 
-func FilterCollection(collection *Collection, collectionID types.UniqueID, collectionName *string) bool {
+// Validate checks the flush result before it is returned to callers. The
+// returned *InvalidFlushError names the first offending field.
+func (f *FlushCollectionInfo) Validate() error {
+	if f.ID == "" {
+		return &InvalidFlushError{Field: "id", Reason: "is empty"}
+	}
+	if _, err := types.Parse(f.ID); err != nil {
+		return &InvalidFlushError{Field: "id", Reason: fmt.Sprintf("%q is not a valid id", f.ID)}
+	}
+	if f.CollectionVersion < 0 {
+		return &InvalidFlushError{Field: "collection_version", Reason: fmt.Sprintf("must not be negative, got %d", f.CollectionVersion)}
+	}
+	if f.TenantLastCompactionTime < 0 {
+		return &InvalidFlushError{Field: "tenant_last_compaction_time", Reason: fmt.Sprintf("must not be negative, got %d", f.TenantLastCompactionTime)}
+	}
+	return nil
+}
+
+// IsDeleted reports whether the collection has been soft deleted.
+func IsDeleted(c *Collection) bool {
+	return c != nil && c.DeletedAt != nil && *c.DeletedAt != 0
+}
+
+type CollectionFilterOption func(*collectionFilterOptions)
+
+type collectionFilterOptions struct {
+	includeDeleted bool
+	readyOnly      bool
+	nameMatch      *NameMatch
+}
+
+// WithIncludeDeleted makes FilterCollection match soft deleted collections,
+// which are excluded by default.
+func WithIncludeDeleted() CollectionFilterOption {
+	return func(o *collectionFilterOptions) {
+		o.includeDeleted = true
+	}
+}
+
+// WithReadyOnly makes FilterCollection skip collections that cannot be
+// queried yet.
+func WithReadyOnly() CollectionFilterOption {
+	return func(o *collectionFilterOptions) {
+		o.readyOnly = true
+	}
+}
+
+// WithNameMatch makes FilterCollection match names by prefix or regular
+// expression in addition to the exact collectionName filter.
+func WithNameMatch(nameMatch *NameMatch) CollectionFilterOption {
+	return func(o *collectionFilterOptions) {
+		o.nameMatch = nameMatch
+	}
+}
+
+// FilterCollection reports whether collection matches the given ID and name.
+// A nil collectionID or collectionName means that field is not filtered on. A
+// nil collection never matches.
+func FilterCollection(collection *Collection, collectionID types.UniqueID, collectionName *string, opts ...CollectionFilterOption) bool {
+	if collection == nil {
+		return false
+	}
+	options := collectionFilterOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if !options.includeDeleted && IsDeleted(collection) {
+		return false
+	}
+	if options.readyOnly && !collection.CanQuery() {
+		return false
+	}
 	if collectionID != types.NilUniqueID() && collectionID != collection.ID {
 		return false
 	}
 	if collectionName != nil && *collectionName != collection.Name {
 		return false
 	}
+	if !options.nameMatch.Matches(collection.Name) {
+		return false
+	}
+	return true
+}
+
+// FilterCollectionByIDs matches collections whose ID is in ids. An empty ids
+// matches every collection. Callers filtering many collections by the same
+// ids should build the set once and use FilterCollectionByIDSet.
+func FilterCollectionByIDs(collection *Collection, ids []types.UniqueID) bool {
+	return FilterCollectionByIDSet(collection, types.NewUniqueIDSet(ids...))
+}
+
+// FilterCollectionByIDSet matches collections whose ID is in ids. A nil or
+// empty set matches every collection.
+func FilterCollectionByIDSet(collection *Collection, ids *types.UniqueIDSet) bool {
+	if collection == nil {
+		return false
+	}
+	return ids.Len() == 0 || ids.Contains(collection.ID)
+}
+
+// FilterCollectionByScope matches collections in the given tenant and
+// database. A nil tenantID or databaseName skips that check.
+func FilterCollectionByScope(collection *Collection, tenantID *string, databaseName *string) bool {
+	if collection == nil {
+		return false
+	}
+	if tenantID != nil && *tenantID != collection.TenantID {
+		return false
+	}
+	if databaseName != nil && *databaseName != collection.DatabaseName {
+		return false
+	}
+	return true
+}
+
+// FilterCollectionByMetadata matches collections whose metadata has every key
+// in metadata with an equal value. An empty metadata predicate matches every
+// collection, including one with nil metadata; any other predicate never
+// matches a collection with nil metadata.
+func FilterCollectionByMetadata(collection *Collection, metadata map[string]CollectionMetadataValueType) bool {
+	if collection == nil {
+		return false
+	}
+	if len(metadata) == 0 {
+		return true
+	}
+	if collection.Metadata == nil {
+		return false
+	}
+	for key, value := range metadata {
+		existing, ok := collection.Metadata.Metadata[key]
+		if !ok || existing == nil || !existing.Equals(value) {
+			return false
+		}
+	}
 	return true
 }
@@ -0,0 +1,51 @@
+package model
+
+import (
+	"github.com/chroma-core/chroma/go/pkg/common"
+	"github.com/chroma-core/chroma/go/pkg/types"
+)
+
+// CollectionAlias is a friendly name pointing at a collection. Aliases follow
+// the collection naming rules and are unique within a tenant's database.
+type CollectionAlias struct {
+	Alias        string
+	CollectionID types.UniqueID
+	TenantID     string
+	DatabaseName string
+}
+
+func (a *CollectionAlias) Validate() error {
+	var violations []error
+	if _, err := NormalizeAndValidateName(a.Alias); err != nil {
+		violations = append(violations, err)
+	}
+	if a.CollectionID == types.NilUniqueID() {
+		violations = append(violations, common.ErrMissingCollectionID)
+	}
+	if a.TenantID == "" {
+		violations = append(violations, common.ErrTenantIDEmpty)
+	}
+	if a.DatabaseName == "" {
+		violations = append(violations, common.ErrDatabaseNameEmpty)
+	}
+	return newValidationError(violations)
+}
+
+// ResolveAlias returns the ID of the collection that alias points to in the
+// given tenant and database. An alias that is not a valid name never
+// resolves.
+func ResolveAlias(aliases []*CollectionAlias, alias string, tenantID string, databaseName string) (types.UniqueID, bool) {
+	normalized, err := NormalizeAndValidateName(alias)
+	if err != nil {
+		return types.NilUniqueID(), false
+	}
+	for _, a := range aliases {
+		if a == nil || a.TenantID != tenantID || a.DatabaseName != databaseName {
+			continue
+		}
+		if a.Alias == normalized {
+			return a.CollectionID, true
+		}
+	}
+	return types.NilUniqueID(), false
+}
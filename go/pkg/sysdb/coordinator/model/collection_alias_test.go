@@ -0,0 +1,57 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/chroma-core/chroma/go/pkg/common"
+	"github.com/chroma-core/chroma/go/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveAlias(t *testing.T) {
+	prodID := types.NewUniqueID()
+	otherID := types.NewUniqueID()
+	aliases := []*CollectionAlias{
+		{Alias: "prod", CollectionID: prodID, TenantID: "tenant", DatabaseName: "database"},
+		{Alias: "prod", CollectionID: otherID, TenantID: "other_tenant", DatabaseName: "database"},
+		nil,
+	}
+
+	// Test case 1: hit, including surrounding whitespace
+	id, ok := ResolveAlias(aliases, "prod", "tenant", "database")
+	assert.True(t, ok)
+	assert.Equal(t, prodID, id)
+	id, ok = ResolveAlias(aliases, " prod ", "other_tenant", "database")
+	assert.True(t, ok)
+	assert.Equal(t, otherID, id)
+
+	// Test case 2: cross tenant and cross database misses
+	_, ok = ResolveAlias(aliases, "prod", "third_tenant", "database")
+	assert.False(t, ok)
+	id, ok = ResolveAlias(aliases, "prod", "tenant", "other_database")
+	assert.False(t, ok)
+	assert.Equal(t, types.NilUniqueID(), id)
+
+	// Test case 3: invalid alias name
+	_, ok = ResolveAlias(aliases, "p", "tenant", "database")
+	assert.False(t, ok)
+}
+
+func TestCollectionAliasValidate(t *testing.T) {
+	// Test case 1: valid alias
+	alias := &CollectionAlias{Alias: "prod", CollectionID: types.NewUniqueID(), TenantID: "tenant", DatabaseName: "database"}
+	assert.NoError(t, alias.Validate())
+
+	// Test case 2: invalid alias name
+	alias.Alias = "_prod"
+	var nameErr *InvalidNameError
+	assert.ErrorAs(t, alias.Validate(), &nameErr)
+	assert.ErrorIs(t, alias.Validate(), common.ErrInvalidName)
+
+	// Test case 3: missing scope
+	alias = &CollectionAlias{Alias: "prod"}
+	err := alias.Validate()
+	assert.ErrorIs(t, err, common.ErrMissingCollectionID)
+	assert.ErrorIs(t, err, common.ErrTenantIDEmpty)
+	assert.ErrorIs(t, err, common.ErrDatabaseNameEmpty)
+}
@@ -0,0 +1,171 @@
+package model
+
+import (
+	"strings"
+
+	"github.com/chroma-core/chroma/go/pkg/common"
+	"github.com/chroma-core/chroma/go/pkg/types"
+)
+
+type BatchCreateCollection struct {
+	Items []*CreateCollection
+}
+
+type collectionNameKey struct {
+	tenantID     string
+	databaseName string
+	name         string
+}
+
+// Validate validates every item and returns the errors aligned with Items,
+// nil where an item is valid. Items reusing a name already taken by an
+// earlier item in the same tenant and database are rejected.
+func (b *BatchCreateCollection) Validate() []error {
+	errs := make([]error, len(b.Items))
+	seen := make(map[collectionNameKey]int, len(b.Items))
+	for i, item := range b.Items {
+		if item == nil {
+			errs[i] = common.ErrCollectionRequestNil
+			continue
+		}
+		errs[i] = item.Validate()
+		key := collectionNameKey{tenantID: item.TenantID, databaseName: item.DatabaseName, name: strings.TrimSpace(item.Name)}
+		if first, ok := seen[key]; ok {
+			errs[i] = appendViolation(errs[i], &DuplicateCollectionNameError{
+				Name:         key.name,
+				TenantID:     item.TenantID,
+				DatabaseName: item.DatabaseName,
+				FirstIndex:   first,
+			})
+			continue
+		}
+		seen[key] = i
+	}
+	return errs
+}
+
+// BatchCreateCollectionResult holds the outcome of each item of a
+// BatchCreateCollection, aligned with its Items.
+type BatchCreateCollectionResult struct {
+	Results []*CreateCollectionResult
+	Errors  []error
+}
+
+func NewBatchCreateCollectionResult(size int) *BatchCreateCollectionResult {
+	return &BatchCreateCollectionResult{
+		Results: make([]*CreateCollectionResult, size),
+		Errors:  make([]error, size),
+	}
+}
+
+func (r *BatchCreateCollectionResult) HasErrors() bool {
+	for _, err := range r.Errors {
+		if err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+type BatchDeleteCollection struct {
+	Items []*DeleteCollection
+}
+
+// Deduplicate drops items whose ID was already requested earlier in the
+// batch, so each collection is deleted at most once.
+func (b *BatchDeleteCollection) Deduplicate() {
+	seen := types.NewUniqueIDSet()
+	items := make([]*DeleteCollection, 0, len(b.Items))
+	for _, item := range b.Items {
+		if item != nil {
+			if seen.Contains(item.ID) {
+				continue
+			}
+			seen.Add(item.ID)
+		}
+		items = append(items, item)
+	}
+	b.Items = items
+}
+
+// Validate validates every item and returns the errors aligned with Items,
+// nil where an item is valid.
+func (b *BatchDeleteCollection) Validate() []error {
+	errs := make([]error, len(b.Items))
+	for i, item := range b.Items {
+		if item == nil {
+			errs[i] = common.ErrCollectionRequestNil
+			continue
+		}
+		errs[i] = item.Validate()
+	}
+	return errs
+}
+
+// BatchDeleteCollectionResult holds the error of each item of a
+// BatchDeleteCollection, aligned with its Items.
+type BatchDeleteCollectionResult struct {
+	Errors []error
+}
+
+func NewBatchDeleteCollectionResult(size int) *BatchDeleteCollectionResult {
+	return &BatchDeleteCollectionResult{
+		Errors: make([]error, size),
+	}
+}
+
+func (r *BatchDeleteCollectionResult) HasErrors() bool {
+	for _, err := range r.Errors {
+		if err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+type BatchUpdateCollection struct {
+	Items []*UpdateCollection
+}
+
+// Validate validates every item against the collection it updates and returns
+// the errors aligned with Items, nil where an item is valid. Items whose ID is
+// not in existing fail with common.ErrCollectionNotFound.
+func (b *BatchUpdateCollection) Validate(existing map[types.UniqueID]*Collection) []error {
+	errs := make([]error, len(b.Items))
+	for i, item := range b.Items {
+		if item == nil {
+			errs[i] = common.ErrCollectionRequestNil
+			continue
+		}
+		collection, ok := existing[item.ID]
+		if !ok || collection == nil {
+			errs[i] = common.ErrCollectionNotFound
+			continue
+		}
+		errs[i] = item.Validate(collection)
+	}
+	return errs
+}
+
+// BatchUpdateCollectionResult holds the outcome of each item of a
+// BatchUpdateCollection, aligned with its Items.
+type BatchUpdateCollectionResult struct {
+	Results []*Collection
+	Errors  []error
+}
+
+func NewBatchUpdateCollectionResult(size int) *BatchUpdateCollectionResult {
+	return &BatchUpdateCollectionResult{
+		Results: make([]*Collection, size),
+		Errors:  make([]error, size),
+	}
+}
+
+func (r *BatchUpdateCollectionResult) HasErrors() bool {
+	for _, err := range r.Errors {
+		if err != nil {
+			return true
+		}
+	}
+	return false
+}
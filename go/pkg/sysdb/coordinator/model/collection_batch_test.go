@@ -0,0 +1,186 @@
+package model
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/chroma-core/chroma/go/pkg/common"
+	"github.com/chroma-core/chroma/go/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestCreateCollection(name string, tenantID string, databaseName string) *CreateCollection {
+	return &CreateCollection{
+		ID:           types.NewUniqueID(),
+		Name:         name,
+		TenantID:     tenantID,
+		DatabaseName: databaseName,
+	}
+}
+
+func TestBatchCreateCollectionValidate(t *testing.T) {
+	// Test case 1: all valid
+	batch := &BatchCreateCollection{Items: []*CreateCollection{
+		newTestCreateCollection("first", "tenant", "database"),
+		newTestCreateCollection("second", "tenant", "database"),
+		newTestCreateCollection("first", "tenant", "other_database"),
+		newTestCreateCollection("first", "other_tenant", "database"),
+	}}
+	errs := batch.Validate()
+	assert.Len(t, errs, 4)
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+
+	// Test case 2: some invalid, errors are aligned by index
+	batch = &BatchCreateCollection{Items: []*CreateCollection{
+		newTestCreateCollection("first", "tenant", "database"),
+		newTestCreateCollection("", "tenant", "database"),
+		nil,
+		newTestCreateCollection("third", "", "database"),
+	}}
+	errs = batch.Validate()
+	assert.Len(t, errs, 4)
+	assert.NoError(t, errs[0])
+	assert.ErrorIs(t, errs[1], common.ErrCollectionNameEmpty)
+	assert.ErrorIs(t, errs[2], common.ErrCollectionRequestNil)
+	assert.ErrorIs(t, errs[3], common.ErrTenantIDEmpty)
+
+	// Test case 3: duplicates within the same tenant and database
+	batch = &BatchCreateCollection{Items: []*CreateCollection{
+		newTestCreateCollection("first", "tenant", "database"),
+		newTestCreateCollection("second", "tenant", "database"),
+		newTestCreateCollection("first", "tenant", "database"),
+		newTestCreateCollection(" second ", "tenant", "database"),
+	}}
+	errs = batch.Validate()
+	assert.NoError(t, errs[0])
+	assert.NoError(t, errs[1])
+	var duplicate *DuplicateCollectionNameError
+	assert.ErrorAs(t, errs[2], &duplicate)
+	assert.Equal(t, 0, duplicate.FirstIndex)
+	assert.ErrorIs(t, errs[2], common.ErrCollectionUniqueConstraintViolation)
+	assert.ErrorAs(t, errs[3], &duplicate)
+	assert.Equal(t, 1, duplicate.FirstIndex)
+
+	// Test case 4: a duplicate that is also invalid reports both violations
+	invalid := newTestCreateCollection("first", "tenant", "database")
+	invalid.ID = types.NilUniqueID()
+	batch = &BatchCreateCollection{Items: []*CreateCollection{
+		newTestCreateCollection("first", "tenant", "database"),
+		invalid,
+	}}
+	errs = batch.Validate()
+	assert.ErrorIs(t, errs[1], common.ErrMissingCollectionID)
+	assert.True(t, errors.As(errs[1], &duplicate))
+}
+
+func TestBatchCreateCollectionResult(t *testing.T) {
+	result := NewBatchCreateCollectionResult(2)
+	assert.Len(t, result.Results, 2)
+	assert.Len(t, result.Errors, 2)
+	assert.False(t, result.HasErrors())
+
+	result.Results[0] = NewCreateCollectionResult(&Collection{Name: "first"}, true)
+	result.Errors[1] = common.ErrCollectionUniqueConstraintViolation
+	assert.True(t, result.HasErrors())
+	assert.True(t, result.Results[0].Created)
+	assert.Nil(t, result.Results[1])
+}
+
+func TestBatchDeleteCollection(t *testing.T) {
+	id1 := types.NewUniqueID()
+	id2 := types.NewUniqueID()
+
+	// Test case 1: fully valid batch
+	batch := &BatchDeleteCollection{Items: []*DeleteCollection{
+		{ID: id1, TenantID: "tenant", DatabaseName: "database"},
+		{ID: id2, TenantID: "tenant", DatabaseName: "database"},
+	}}
+	for _, err := range batch.Validate() {
+		assert.NoError(t, err)
+	}
+
+	// Test case 2: dedup keeps the first occurrence of each ID
+	batch = &BatchDeleteCollection{Items: []*DeleteCollection{
+		{ID: id1, TenantID: "tenant", DatabaseName: "database"},
+		{ID: id2, TenantID: "tenant", DatabaseName: "database"},
+		{ID: id1, TenantID: "tenant", DatabaseName: "database", SoftDelete: true},
+	}}
+	batch.Deduplicate()
+	assert.Len(t, batch.Items, 2)
+	assert.Equal(t, id1, batch.Items[0].ID)
+	assert.False(t, batch.Items[0].SoftDelete)
+	assert.Equal(t, id2, batch.Items[1].ID)
+
+	// Test case 3: validation failures are aligned by index
+	batch = &BatchDeleteCollection{Items: []*DeleteCollection{
+		{ID: types.NilUniqueID(), TenantID: "tenant", DatabaseName: "database"},
+		{ID: id1, TenantID: "", DatabaseName: "database"},
+		{ID: id2, TenantID: "tenant", DatabaseName: ""},
+		nil,
+		{ID: types.NewUniqueID(), TenantID: "tenant", DatabaseName: "database"},
+	}}
+	errs := batch.Validate()
+	assert.Len(t, errs, 5)
+	assert.ErrorIs(t, errs[0], common.ErrMissingCollectionID)
+	assert.ErrorIs(t, errs[1], common.ErrTenantIDEmpty)
+	assert.ErrorIs(t, errs[2], common.ErrDatabaseNameEmpty)
+	assert.ErrorIs(t, errs[3], common.ErrCollectionRequestNil)
+	assert.NoError(t, errs[4])
+
+	// Test case 4: result carries per-item errors
+	result := NewBatchDeleteCollectionResult(2)
+	assert.False(t, result.HasErrors())
+	result.Errors[1] = common.ErrCollectionDeleteNonExistingCollection
+	assert.True(t, result.HasErrors())
+}
+
+func TestBatchUpdateCollectionValidate(t *testing.T) {
+	id1 := types.NewUniqueID()
+	id2 := types.NewUniqueID()
+	existing := map[types.UniqueID]*Collection{
+		id1: {ID: id1, Name: "first", TenantID: "tenant", DatabaseName: "database", Dimension: int32Ptr(3)},
+		id2: {ID: id2, Name: "second", TenantID: "tenant", DatabaseName: "database"},
+	}
+
+	// Test case 1: all items valid
+	batch := &BatchUpdateCollection{Items: []*UpdateCollection{
+		{ID: id1, TenantID: "tenant", DatabaseName: "database", Name: stringPtr("renamed")},
+		{ID: id2, TenantID: "tenant", DatabaseName: "database", Dimension: int32Ptr(8)},
+	}}
+	errs := batch.Validate(existing)
+	assert.Len(t, errs, 2)
+	assert.NoError(t, errs[0])
+	assert.NoError(t, errs[1])
+
+	// Test case 2: an item whose ID is not in existing is not found
+	batch = &BatchUpdateCollection{Items: []*UpdateCollection{
+		{ID: types.NewUniqueID(), TenantID: "tenant", DatabaseName: "database"},
+		{ID: id1, TenantID: "tenant", DatabaseName: "database"},
+		nil,
+	}}
+	errs = batch.Validate(existing)
+	assert.Len(t, errs, 3)
+	assert.ErrorIs(t, errs[0], common.ErrCollectionNotFound)
+	assert.NoError(t, errs[1])
+	assert.ErrorIs(t, errs[2], common.ErrCollectionRequestNil)
+
+	// Test case 3: changing an established dimension fails in its slot only
+	batch = &BatchUpdateCollection{Items: []*UpdateCollection{
+		{ID: id2, TenantID: "tenant", DatabaseName: "database"},
+		{ID: id1, TenantID: "tenant", DatabaseName: "database", Dimension: int32Ptr(4)},
+	}}
+	errs = batch.Validate(existing)
+	assert.Len(t, errs, 2)
+	assert.NoError(t, errs[0])
+	assert.ErrorIs(t, errs[1], common.ErrInvalidDimension)
+	var mismatch *DimensionMismatchError
+	assert.ErrorAs(t, errs[1], &mismatch)
+
+	// Test case 4: result carries per-item errors
+	result := NewBatchUpdateCollectionResult(2)
+	assert.False(t, result.HasErrors())
+	result.Errors[0] = common.ErrCollectionNotFound
+	assert.True(t, result.HasErrors())
+}
@@ -0,0 +1,161 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"math"
+	"sort"
+
+	"github.com/chroma-core/chroma/go/pkg/types"
+)
+
+// Checksum returns a SHA-256 hex digest of the collection's fields. It is
+// consistent with Equal: equal collections have the same checksum regardless
+// of map iteration order, and any field difference changes it. A nil
+// collection has the checksum of the "nil" sentinel.
+func (c *Collection) Checksum() string {
+	h := sha256.New()
+	if c == nil {
+		writeChecksumField(h, "collection", nil)
+		return hex.EncodeToString(h.Sum(nil))
+	}
+	writeChecksumField(h, "id", c.ID.String())
+	writeChecksumField(h, "name", c.Name)
+	writeChecksumField(h, "configuration_json_str", c.ConfigurationJsonStr)
+	if c.Configuration == nil {
+		writeChecksumField(h, "configuration", nil)
+	} else {
+		writeChecksumField(h, "hnsw_m", c.Configuration.HnswM)
+		writeChecksumField(h, "hnsw_construction_ef", c.Configuration.HnswConstructionEf)
+		writeChecksumField(h, "hnsw_search_ef", c.Configuration.HnswSearchEf)
+		writeChecksumField(h, "space", c.Configuration.Space)
+	}
+	writeChecksumField(h, "distance_function", c.DistanceFunction)
+	writeChecksumField(h, "embedding_function", c.EmbeddingFunction)
+	writeChecksumField(h, "dimension", c.Dimension)
+	writeChecksumMetadata(h, c.Metadata)
+	writeChecksumLabels(h, c.Labels)
+	writeChecksumField(h, "tenant_id", c.TenantID)
+	writeChecksumField(h, "database_name", c.DatabaseName)
+	writeChecksumField(h, "ts", int64(c.Ts))
+	writeChecksumField(h, "log_position", c.LogPosition)
+	writeChecksumField(h, "last_compaction_time", c.LastCompactionTime)
+	writeChecksumField(h, "version", c.Version)
+	writeChecksumField(h, "update_version", c.UpdateVersion)
+	writeChecksumField(h, "deleted_at", c.DeletedAt)
+	writeChecksumField(h, "expires_at", c.ExpiresAt)
+	writeChecksumField(h, "state", int32(c.State))
+	if c.SourceCollectionID == nil {
+		writeChecksumField(h, "source_collection_id", nil)
+	} else {
+		writeChecksumField(h, "source_collection_id", c.SourceCollectionID.String())
+	}
+	writeChecksumField(h, "forked_at", c.ForkedAt)
+	writeChecksumField(h, "read_only", c.ReadOnly)
+	if c.Stats == nil {
+		writeChecksumField(h, "stats", nil)
+	} else {
+		writeChecksumField(h, "stats", fmt.Sprintf("%d/%d/%d", c.Stats.RecordCount, c.Stats.SegmentCount, c.Stats.LogicalSizeBytes))
+	}
+	writeChecksumField(h, "created_by", c.CreatedBy)
+	writeChecksumField(h, "updated_by", c.UpdatedBy)
+	writeChecksumField(h, "requires_reindex", c.RequiresReindex)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// writeChecksumField writes one named value. Strings are quoted and nil
+// pointers are written as the bare nil sentinel, so no two distinct values
+// share an encoding.
+func writeChecksumField(h hash.Hash, name string, value interface{}) {
+	switch v := value.(type) {
+	case nil:
+		fmt.Fprintf(h, "%s=nil;", name)
+	case string:
+		fmt.Fprintf(h, "%s=%q;", name, v)
+	case *string:
+		if v == nil {
+			fmt.Fprintf(h, "%s=nil;", name)
+		} else {
+			fmt.Fprintf(h, "%s=%q;", name, *v)
+		}
+	case *int32:
+		if v == nil {
+			fmt.Fprintf(h, "%s=nil;", name)
+		} else {
+			fmt.Fprintf(h, "%s=%d;", name, *v)
+		}
+	case *types.Timestamp:
+		if v == nil {
+			fmt.Fprintf(h, "%s=nil;", name)
+		} else {
+			fmt.Fprintf(h, "%s=%d;", name, int64(*v))
+		}
+	default:
+		fmt.Fprintf(h, "%s=%v;", name, v)
+	}
+}
+
+func writeChecksumMetadata(h hash.Hash, metadata *CollectionMetadata[CollectionMetadataValueType]) {
+	if metadata == nil {
+		writeChecksumField(h, "metadata", nil)
+		return
+	}
+	keys := metadata.SortedKeys()
+	fmt.Fprintf(h, "metadata=%d{", len(keys))
+	for _, key := range keys {
+		writeChecksumField(h, fmt.Sprintf("%q", key), checksumMetadataValue(metadata.Metadata[key]))
+	}
+	fmt.Fprint(h, "};")
+}
+
+// checksumMetadataValue encodes a value with its type. Lists are encoded as
+// sorted sets and floats are snapped to the FloatMetadataEpsilon grid,
+// matching the value Equals methods.
+func checksumMetadataValue(value CollectionMetadataValueType) string {
+	switch v := value.(type) {
+	case *CollectionMetadataValueStringType:
+		return fmt.Sprintf("string:%q", v.Value)
+	case *CollectionMetadataValueInt64Type:
+		return fmt.Sprintf("int:%d", v.Value)
+	case *CollectionMetadataValueFloat64Type:
+		f := quantizeFloat(v.Value)
+		if f == 0 {
+			f = 0
+		}
+		return fmt.Sprintf("float:%x", math.Float64bits(f))
+	case *CollectionMetadataValueBoolType:
+		return fmt.Sprintf("bool:%t", v.Value)
+	case *CollectionMetadataValueStringListType:
+		set := make(map[string]struct{}, len(v.Value))
+		for _, element := range v.Value {
+			set[element] = struct{}{}
+		}
+		elements := make([]string, 0, len(set))
+		for element := range set {
+			elements = append(elements, element)
+		}
+		sort.Strings(elements)
+		return fmt.Sprintf("string_list:%q", elements)
+	case *CollectionMetadataValueNoneType:
+		return "none"
+	case *CollectionMetadataValueDeleteType:
+		return "delete"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func writeChecksumLabels(h hash.Hash, labels map[string]string) {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	fmt.Fprintf(h, "labels=%d{", len(keys))
+	for _, key := range keys {
+		fmt.Fprintf(h, "%q=%q;", key, labels[key])
+	}
+	fmt.Fprint(h, "};")
+}
@@ -0,0 +1,120 @@
+package model
+
+import (
+	"math"
+	"testing"
+
+	"github.com/chroma-core/chroma/go/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func newChecksumTestCollection() *Collection {
+	metadata := NewCollectionMetadata[CollectionMetadataValueType]()
+	metadata.Add("string", &CollectionMetadataValueStringType{Value: "1"})
+	metadata.Add("int", &CollectionMetadataValueInt64Type{Value: 1})
+	metadata.Add("list", &CollectionMetadataValueStringListType{Value: []string{"a", "b"}})
+	return &Collection{
+		ID:            types.MustParse("00000000-0000-0000-0000-000000000001"),
+		Name:          "collection",
+		Configuration: &CollectionConfiguration{HnswM: int32Ptr(16)},
+		Dimension:     int32Ptr(128),
+		Metadata:      metadata,
+		Labels:        map[string]string{"env": "prod", "team": "search"},
+		TenantID:      "tenant",
+		DatabaseName:  "database",
+		Ts:            5,
+		LogPosition:   3,
+		Version:       2,
+	}
+}
+
+func TestCollectionChecksumStable(t *testing.T) {
+	// Test case 1: stable across calls and runs
+	checksum := newChecksumTestCollection().Checksum()
+	assert.Len(t, checksum, 64)
+	assert.Equal(t, checksum, newChecksumTestCollection().Checksum())
+	assert.Equal(t, "0c4bdb834d6b9ed778aa9e0bc7f4ec39b57384bcb4f550ab0befd423224d7f7b", checksum)
+
+	// Test case 2: independent of insertion order and list order
+	reordered := newChecksumTestCollection()
+	reordered.Metadata = NewCollectionMetadata[CollectionMetadataValueType]()
+	reordered.Metadata.Add("list", &CollectionMetadataValueStringListType{Value: []string{"b", "a", "b"}})
+	reordered.Metadata.Add("int", &CollectionMetadataValueInt64Type{Value: 1})
+	reordered.Metadata.Add("string", &CollectionMetadataValueStringType{Value: "1"})
+	reordered.Labels = map[string]string{"team": "search", "env": "prod"}
+	assert.True(t, newChecksumTestCollection().Equal(reordered))
+	assert.Equal(t, checksum, reordered.Checksum())
+
+	// Test case 3: equal floats share a checksum
+	positive := &Collection{Metadata: NewCollectionMetadata[CollectionMetadataValueType]()}
+	positive.Metadata.Add("zero", &CollectionMetadataValueFloat64Type{Value: 0})
+	negative := &Collection{Metadata: NewCollectionMetadata[CollectionMetadataValueType]()}
+	negative.Metadata.Add("zero", &CollectionMetadataValueFloat64Type{Value: math.Copysign(0, -1)})
+	assert.True(t, positive.Equal(negative))
+	assert.Equal(t, positive.Checksum(), negative.Checksum())
+
+	// Test case 4: nil collection
+	var nilCollection *Collection
+	assert.Equal(t, nilCollection.Checksum(), nilCollection.Checksum())
+	assert.NotEqual(t, nilCollection.Checksum(), (&Collection{}).Checksum())
+}
+
+func TestCollectionChecksumSensitivity(t *testing.T) {
+	timestamp := types.Timestamp(10)
+	sourceID := types.NewUniqueID()
+	tests := []struct {
+		name   string
+		mutate func(c *Collection)
+	}{
+		{"id", func(c *Collection) { c.ID = types.NewUniqueID() }},
+		{"name", func(c *Collection) { c.Name = "other" }},
+		{"configuration json", func(c *Collection) { c.ConfigurationJsonStr = "{}" }},
+		{"nil configuration", func(c *Collection) { c.Configuration = nil }},
+		{"empty configuration", func(c *Collection) { c.Configuration = &CollectionConfiguration{} }},
+		{"configuration field", func(c *Collection) { c.Configuration.HnswSearchEf = int32Ptr(16) }},
+		{"distance function", func(c *Collection) { c.DistanceFunction = stringPtr(SpaceIP) }},
+		{"embedding function", func(c *Collection) { c.EmbeddingFunction = stringPtr("ef") }},
+		{"nil dimension", func(c *Collection) { c.Dimension = nil }},
+		{"dimension", func(c *Collection) { c.Dimension = int32Ptr(256) }},
+		{"nil metadata", func(c *Collection) { c.Metadata = nil }},
+		{"empty metadata", func(c *Collection) { c.Metadata = NewCollectionMetadata[CollectionMetadataValueType]() }},
+		{"metadata value", func(c *Collection) { c.Metadata.Add("int", &CollectionMetadataValueInt64Type{Value: 2}) }},
+		{"metadata type", func(c *Collection) { c.Metadata.Add("int", &CollectionMetadataValueStringType{Value: "1"}) }},
+		{"metadata list", func(c *Collection) {
+			c.Metadata.Add("list", &CollectionMetadataValueStringListType{Value: []string{"a"}})
+		}},
+		{"metadata key", func(c *Collection) { c.Metadata.Remove("string") }},
+		{"labels", func(c *Collection) { c.Labels["env"] = "dev" }},
+		{"tenant", func(c *Collection) { c.TenantID = "other" }},
+		{"database", func(c *Collection) { c.DatabaseName = "other" }},
+		{"ts", func(c *Collection) { c.Ts = 6 }},
+		{"log position", func(c *Collection) { c.LogPosition = 4 }},
+		{"last compaction time", func(c *Collection) { c.LastCompactionTime = 1 }},
+		{"version", func(c *Collection) { c.Version = 3 }},
+		{"update version", func(c *Collection) { c.UpdateVersion = 1 }},
+		{"deleted at", func(c *Collection) { c.DeletedAt = &timestamp }},
+		{"expires at", func(c *Collection) { c.ExpiresAt = &timestamp }},
+		{"state", func(c *Collection) { c.State = CollectionStateCreating }},
+		{"source collection", func(c *Collection) { c.SourceCollectionID = &sourceID }},
+		{"forked at", func(c *Collection) { c.ForkedAt = &timestamp }},
+		{"read only", func(c *Collection) { c.ReadOnly = true }},
+		{"stats", func(c *Collection) { c.Stats = &CollectionStats{} }},
+		{"created by", func(c *Collection) { c.CreatedBy = "alice" }},
+		{"updated by", func(c *Collection) { c.UpdatedBy = "bob" }},
+		{"requires reindex", func(c *Collection) { c.RequiresReindex = true }},
+	}
+	original := newChecksumTestCollection().Checksum()
+	seen := map[string]string{original: "original"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mutated := newChecksumTestCollection()
+			tt.mutate(mutated)
+			assert.False(t, newChecksumTestCollection().Equal(mutated))
+			checksum := mutated.Checksum()
+			assert.NotEqual(t, original, checksum)
+			previous, ok := seen[checksum]
+			assert.False(t, ok, "collides with %s", previous)
+			seen[checksum] = tt.name
+		})
+	}
+}
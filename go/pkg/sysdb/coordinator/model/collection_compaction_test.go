@@ -0,0 +1,220 @@
+package model
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/chroma-core/chroma/go/pkg/common"
+	"github.com/chroma-core/chroma/go/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlushCollectionCompactionValidate(t *testing.T) {
+	// Test case 1: advancing log position with the current version
+	flush := &FlushCollectionCompaction{LogPosition: 20, CurrentCollectionVersion: 3}
+	assert.NoError(t, flush.Validate(10, 3))
+
+	// Test case 2: equal log position is an idempotent reflush
+	flush = &FlushCollectionCompaction{LogPosition: 10, CurrentCollectionVersion: 3}
+	assert.NoError(t, flush.Validate(10, 3))
+
+	// Test case 3: rewinding the log position is rejected
+	flush = &FlushCollectionCompaction{LogPosition: 5, CurrentCollectionVersion: 3}
+	err := flush.Validate(10, 3)
+	var regression *LogPositionRegressionError
+	assert.ErrorAs(t, err, &regression)
+	assert.Equal(t, int64(10), regression.Current)
+	assert.Equal(t, int64(5), regression.Requested)
+	assert.ErrorIs(t, err, common.ErrCollectionLogPositionStale)
+	var mismatch *VersionMismatchError
+	assert.False(t, errors.As(err, &mismatch))
+
+	// Test case 4: a stale version is a distinct error
+	flush = &FlushCollectionCompaction{LogPosition: 20, CurrentCollectionVersion: 2}
+	err = flush.Validate(10, 3)
+	assert.ErrorAs(t, err, &mismatch)
+	assert.Equal(t, int32(3), mismatch.Current)
+	assert.Equal(t, int32(2), mismatch.Requested)
+	assert.ErrorIs(t, err, common.ErrCollectionVersionStale)
+	assert.False(t, errors.As(err, &regression))
+
+	// Test case 5: a version ahead of the collection is invalid
+	flush = &FlushCollectionCompaction{LogPosition: 20, CurrentCollectionVersion: 4}
+	err = flush.Validate(10, 3)
+	assert.ErrorAs(t, err, &mismatch)
+	assert.ErrorIs(t, err, common.ErrCollectionVersionInvalid)
+}
+
+func TestFlushCollectionCompactionSegmentTimes(t *testing.T) {
+	// Test case 1: no segments
+	flush := &FlushCollectionCompaction{}
+	assert.Equal(t, int64(0), flush.MaxSegmentCompactionTime())
+
+	// Test case 2: max across segments
+	flush = &FlushCollectionCompaction{
+		LogPosition:              20,
+		CurrentCollectionVersion: 3,
+		FlushSegmentCompactions: []*FlushSegmentCompaction{
+			{ID: types.NewUniqueID(), LastCompactionTime: 100},
+			{ID: types.NewUniqueID(), LastCompactionTime: 300},
+			{ID: types.NewUniqueID(), LastCompactionTime: 200},
+		},
+	}
+	assert.Equal(t, int64(300), flush.MaxSegmentCompactionTime())
+	assert.NoError(t, flush.Validate(10, 3))
+
+	// Test case 3: negative time is rejected
+	flush.FlushSegmentCompactions[1].LastCompactionTime = -1
+	err := flush.Validate(10, 3)
+	var invalid *InvalidFlushError
+	assert.ErrorAs(t, err, &invalid)
+	assert.Equal(t, "last_compaction_time", invalid.Field)
+	assert.ErrorIs(t, err, common.ErrInvalidFlush)
+}
+
+func TestFlushCollectionCompactionRecordsCompacted(t *testing.T) {
+	// Test case 1: zero total with no segments
+	flush := &FlushCollectionCompaction{LogPosition: 20, CurrentCollectionVersion: 3}
+	assert.NoError(t, flush.Validate(10, 3))
+
+	// Test case 2: total equals the sum of segment counts
+	flush.FlushSegmentCompactions = []*FlushSegmentCompaction{
+		{ID: types.NewUniqueID(), RecordsCompacted: 100},
+		{ID: types.NewUniqueID(), RecordsCompacted: 50},
+		{ID: types.NewUniqueID()},
+	}
+	flush.TotalRecordsCompacted = 150
+	assert.NoError(t, flush.Validate(10, 3))
+
+	// Test case 3: mismatched total
+	flush.TotalRecordsCompacted = 149
+	err := flush.Validate(10, 3)
+	var invalid *InvalidFlushError
+	assert.ErrorAs(t, err, &invalid)
+	assert.Equal(t, "total_records_compacted", invalid.Field)
+	assert.ErrorIs(t, err, common.ErrInvalidFlush)
+
+	// Test case 4: a total without segments
+	flush = &FlushCollectionCompaction{LogPosition: 20, CurrentCollectionVersion: 3, TotalRecordsCompacted: 1}
+	assert.ErrorIs(t, flush.Validate(10, 3), common.ErrInvalidFlush)
+}
+
+func TestFlushCollectionCompactionAttempts(t *testing.T) {
+	flush := &FlushCollectionCompaction{LogPosition: 20, CurrentCollectionVersion: 3}
+
+	// Test case 1: NextAttempt increments a copy
+	next := flush.NextAttempt()
+	assert.Equal(t, int32(1), next.Attempt)
+	assert.Equal(t, int64(20), next.LogPosition)
+	assert.Equal(t, int32(0), flush.Attempt)
+	next = next.NextAttempt()
+	assert.Equal(t, int32(2), next.Attempt)
+
+	// Test case 2: give up once maxAttempts is reached
+	assert.False(t, flush.ShouldGiveUp(2))
+	assert.False(t, (&FlushCollectionCompaction{Attempt: 1}).ShouldGiveUp(2))
+	assert.True(t, next.ShouldGiveUp(2))
+	assert.True(t, (&FlushCollectionCompaction{Attempt: 3}).ShouldGiveUp(2))
+
+	// Test case 3: negative attempt is rejected
+	flush.Attempt = -1
+	err := flush.Validate(10, 3)
+	var invalid *InvalidFlushError
+	assert.ErrorAs(t, err, &invalid)
+	assert.Equal(t, "attempt", invalid.Field)
+}
+
+func TestFlushCollectionCompactionRollback(t *testing.T) {
+	// Test case 1: rollback restores the previous log position
+	flush := &FlushCollectionCompaction{LogPosition: 20, PreviousLogPosition: 10, CurrentCollectionVersion: 3}
+	assert.NoError(t, flush.Validate(10, 3))
+	rolledBack := flush.Rollback()
+	assert.Equal(t, int64(10), rolledBack.LogPosition)
+	assert.Equal(t, int64(10), rolledBack.PreviousLogPosition)
+	assert.Equal(t, int32(3), rolledBack.CurrentCollectionVersion)
+	assert.Equal(t, int64(20), flush.LogPosition)
+
+	// Test case 2: previous log position ahead of the new one
+	flush = &FlushCollectionCompaction{LogPosition: 20, PreviousLogPosition: 30, CurrentCollectionVersion: 3}
+	err := flush.Validate(10, 3)
+	var invalid *InvalidFlushError
+	assert.ErrorAs(t, err, &invalid)
+	assert.Equal(t, "previous_log_position", invalid.Field)
+	assert.ErrorIs(t, err, common.ErrInvalidFlush)
+}
+
+func TestFlushCollectionInfoValidate(t *testing.T) {
+	valid := FlushCollectionInfo{
+		ID:                       types.NewUniqueID().String(),
+		CollectionVersion:        3,
+		TenantLastCompactionTime: 100,
+	}
+	assert.NoError(t, valid.Validate())
+
+	tests := []struct {
+		name   string
+		mutate func(f *FlushCollectionInfo)
+		field  string
+	}{
+		{"empty id", func(f *FlushCollectionInfo) { f.ID = "" }, "id"},
+		{"malformed id", func(f *FlushCollectionInfo) { f.ID = "not-a-uuid" }, "id"},
+		{"negative version", func(f *FlushCollectionInfo) { f.CollectionVersion = -1 }, "collection_version"},
+		{"negative compaction time", func(f *FlushCollectionInfo) { f.TenantLastCompactionTime = -1 }, "tenant_last_compaction_time"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := valid
+			tt.mutate(&info)
+			err := info.Validate()
+			var invalid *InvalidFlushError
+			assert.ErrorAs(t, err, &invalid)
+			assert.Equal(t, tt.field, invalid.Field)
+			assert.ErrorIs(t, err, common.ErrInvalidFlush)
+		})
+	}
+}
+
+func TestCollectionCompactionAge(t *testing.T) {
+	// Test case 1: never compacted
+	assert.Equal(t, int64(0), (&Collection{}).CompactionAge(100))
+
+	// Test case 2: age since the last compaction
+	collection := &Collection{LastCompactionTime: 40}
+	assert.Equal(t, int64(60), collection.CompactionAge(100))
+	assert.Equal(t, int64(0), collection.CompactionAge(40))
+
+	// Test case 3: clock skew clamps to 0
+	assert.Equal(t, int64(0), collection.CompactionAge(10))
+}
+
+func TestFlushCollectionCompactionResolveConflict(t *testing.T) {
+	tests := []struct {
+		name           string
+		flushVersion   int32
+		currentVersion int32
+		strategy       ConflictStrategy
+		proceed        bool
+		err            error
+	}{
+		{name: "abort matching", flushVersion: 2, currentVersion: 2, strategy: ConflictAbort, proceed: true},
+		{name: "abort mismatching", flushVersion: 1, currentVersion: 2, strategy: ConflictAbort, err: common.ErrCollectionVersionStale},
+		{name: "retry matching", flushVersion: 2, currentVersion: 2, strategy: ConflictRetry, proceed: true},
+		{name: "retry mismatching", flushVersion: 1, currentVersion: 2, strategy: ConflictRetry},
+		{name: "take higher matching", flushVersion: 2, currentVersion: 2, strategy: ConflictTakeHigherVersion, proceed: true},
+		{name: "take higher ahead", flushVersion: 3, currentVersion: 2, strategy: ConflictTakeHigherVersion, proceed: true},
+		{name: "take higher behind", flushVersion: 1, currentVersion: 2, strategy: ConflictTakeHigherVersion, err: common.ErrCollectionVersionStale},
+		{name: "unknown strategy", flushVersion: 1, currentVersion: 2, strategy: ConflictStrategy(99), err: common.ErrInvalidFlush},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flush := &FlushCollectionCompaction{CurrentCollectionVersion: tt.flushVersion}
+			proceed, err := flush.ResolveConflict(tt.currentVersion, tt.strategy)
+			assert.Equal(t, tt.proceed, proceed)
+			if tt.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.err)
+			}
+		})
+	}
+}
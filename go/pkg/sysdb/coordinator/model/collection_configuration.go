@@ -0,0 +1,169 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/chroma-core/chroma/go/pkg/types"
+)
+
+const (
+	SpaceL2     = "l2"
+	SpaceCosine = "cosine"
+	SpaceIP     = "ip"
+
+	DefaultDistanceFunction = SpaceL2
+
+	MinHnswM = 2
+	MaxHnswM = 2048
+
+	MaxEmbeddingFunctionBytes = 128
+)
+
+// CollectionConfiguration holds the index parameters of a collection. A nil
+// field means the system default is used.
+type CollectionConfiguration struct {
+	HnswM              *int32
+	HnswConstructionEf *int32
+	HnswSearchEf       *int32
+	Space              *string
+}
+
+func isValidSpace(space string) bool {
+	switch space {
+	case SpaceL2, SpaceCosine, SpaceIP:
+		return true
+	default:
+		return false
+	}
+}
+
+// NormalizeDistanceFunction lowercases distanceFunction and checks that it is
+// one of the supported spaces. The same case rule applies to
+// CollectionConfiguration.Space, and both are stored in lowercase.
+func NormalizeDistanceFunction(distanceFunction string) (string, error) {
+	normalized := strings.ToLower(distanceFunction)
+	if !isValidSpace(normalized) {
+		return "", &InvalidDistanceFunctionError{DistanceFunction: distanceFunction}
+	}
+	return normalized, nil
+}
+
+// normalizeSpace returns a lowercased copy of a distance function or space
+// for storage, or nil when space is unset.
+func normalizeSpace(space *string) *string {
+	if space == nil {
+		return nil
+	}
+	normalized := strings.ToLower(*space)
+	return &normalized
+}
+
+// ValidateEmbeddingFunction checks the name of an embedding function bound to
+// a collection. A nil name means the embedding function is managed by the
+// client.
+func ValidateEmbeddingFunction(embeddingFunction *string) error {
+	if embeddingFunction == nil {
+		return nil
+	}
+	if *embeddingFunction == "" {
+		return &InvalidEmbeddingFunctionError{Reason: "must not be empty"}
+	}
+	if len(*embeddingFunction) > MaxEmbeddingFunctionBytes {
+		return &InvalidEmbeddingFunctionError{EmbeddingFunction: *embeddingFunction, Reason: fmt.Sprintf("must be at most %d bytes", MaxEmbeddingFunctionBytes)}
+	}
+	return nil
+}
+
+func (c *CollectionConfiguration) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.HnswM != nil && (*c.HnswM < MinHnswM || *c.HnswM > MaxHnswM) {
+		return &InvalidConfigurationError{Field: "hnsw_m", Reason: fmt.Sprintf("must be between %d and %d, got %d", MinHnswM, MaxHnswM, *c.HnswM)}
+	}
+	if c.HnswConstructionEf != nil && *c.HnswConstructionEf <= 0 {
+		return &InvalidConfigurationError{Field: "hnsw_construction_ef", Reason: fmt.Sprintf("must be positive, got %d", *c.HnswConstructionEf)}
+	}
+	if c.HnswSearchEf != nil && *c.HnswSearchEf <= 0 {
+		return &InvalidConfigurationError{Field: "hnsw_search_ef", Reason: fmt.Sprintf("must be positive, got %d", *c.HnswSearchEf)}
+	}
+	if c.Space != nil && !isValidSpace(strings.ToLower(*c.Space)) {
+		return &InvalidConfigurationError{Field: "space", Reason: fmt.Sprintf("must be one of %s, %s or %s, got %q", SpaceL2, SpaceCosine, SpaceIP, *c.Space)}
+	}
+	return nil
+}
+
+// normalized returns a clone of the configuration with Space in lowercase.
+func (c *CollectionConfiguration) normalized() *CollectionConfiguration {
+	normalized := c.Clone()
+	if normalized != nil {
+		normalized.Space = normalizeSpace(c.Space)
+	}
+	return normalized
+}
+
+func (c *CollectionConfiguration) Clone() *CollectionConfiguration {
+	if c == nil {
+		return nil
+	}
+	return &CollectionConfiguration{
+		HnswM:              cloneInt32(c.HnswM),
+		HnswConstructionEf: cloneInt32(c.HnswConstructionEf),
+		HnswSearchEf:       cloneInt32(c.HnswSearchEf),
+		Space:              cloneString(c.Space),
+	}
+}
+
+// Equal reports whether both configurations set the same fields to the same
+// values. Two nil configurations are equal.
+func (c *CollectionConfiguration) Equal(other *CollectionConfiguration) bool {
+	if c == nil || other == nil {
+		return c == other
+	}
+	return equalPtr(c.HnswM, other.HnswM) &&
+		equalPtr(c.HnswConstructionEf, other.HnswConstructionEf) &&
+		equalPtr(c.HnswSearchEf, other.HnswSearchEf) &&
+		equalPtr(c.Space, other.Space)
+}
+
+func cloneInt32(v *int32) *int32 {
+	if v == nil {
+		return nil
+	}
+	clone := *v
+	return &clone
+}
+
+func cloneString(v *string) *string {
+	if v == nil {
+		return nil
+	}
+	clone := *v
+	return &clone
+}
+
+func cloneTimestamp(v *types.Timestamp) *types.Timestamp {
+	if v == nil {
+		return nil
+	}
+	clone := *v
+	return &clone
+}
+
+func cloneUniqueID(v *types.UniqueID) *types.UniqueID {
+	if v == nil {
+		return nil
+	}
+	clone := *v
+	return &clone
+}
+
+// equalPtr compares the values behind two pointers. Two nil pointers are
+// equal.
+func equalPtr[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
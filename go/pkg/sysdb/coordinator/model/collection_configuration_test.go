@@ -0,0 +1,165 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func int32Ptr(v int32) *int32 {
+	return &v
+}
+
+func stringPtr(v string) *string {
+	return &v
+}
+
+func TestCollectionConfigurationValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *CollectionConfiguration
+		field  string
+	}{
+		{name: "nil config", config: nil},
+		{name: "system defaults", config: &CollectionConfiguration{}},
+		{
+			name: "fully specified",
+			config: &CollectionConfiguration{
+				HnswM:              int32Ptr(16),
+				HnswConstructionEf: int32Ptr(100),
+				HnswSearchEf:       int32Ptr(10),
+				Space:              stringPtr(SpaceCosine),
+			},
+		},
+		{name: "minimum m", config: &CollectionConfiguration{HnswM: int32Ptr(2)}},
+		{name: "maximum m", config: &CollectionConfiguration{HnswM: int32Ptr(2048)}},
+		{name: "ip space", config: &CollectionConfiguration{Space: stringPtr(SpaceIP)}},
+		{name: "l2 space", config: &CollectionConfiguration{Space: stringPtr(SpaceL2)}},
+		{name: "uppercase space", config: &CollectionConfiguration{Space: stringPtr("COSINE")}},
+		{name: "m too small", config: &CollectionConfiguration{HnswM: int32Ptr(1)}, field: "hnsw_m"},
+		{name: "m too large", config: &CollectionConfiguration{HnswM: int32Ptr(2049)}, field: "hnsw_m"},
+		{name: "zero construction ef", config: &CollectionConfiguration{HnswConstructionEf: int32Ptr(0)}, field: "hnsw_construction_ef"},
+		{name: "negative construction ef", config: &CollectionConfiguration{HnswConstructionEf: int32Ptr(-1)}, field: "hnsw_construction_ef"},
+		{name: "zero search ef", config: &CollectionConfiguration{HnswSearchEf: int32Ptr(0)}, field: "hnsw_search_ef"},
+		{name: "unknown space", config: &CollectionConfiguration{Space: stringPtr("manhattan")}, field: "space"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.field == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var configErr *InvalidConfigurationError
+			assert.ErrorAs(t, err, &configErr)
+			assert.Equal(t, tt.field, configErr.Field)
+		})
+	}
+}
+
+func TestCollectionConfigurationInCollection(t *testing.T) {
+	config := &CollectionConfiguration{HnswM: int32Ptr(16), Space: stringPtr(SpaceIP)}
+
+	// Test case 1: clone deep copies the configuration
+	collection := &Collection{Configuration: config}
+	clone := collection.Clone()
+	*clone.Configuration.HnswM = 32
+	*clone.Configuration.Space = SpaceL2
+	assert.Equal(t, int32(16), *collection.Configuration.HnswM)
+	assert.Equal(t, SpaceIP, *collection.Configuration.Space)
+
+	// Test case 2: create and update validation reject an invalid configuration
+	invalid := &CollectionConfiguration{HnswM: int32Ptr(0)}
+	var configErr *InvalidConfigurationError
+	create := &CreateCollection{Name: "collection", Configuration: invalid}
+	assert.ErrorAs(t, create.Validate(), &configErr)
+	update := &UpdateCollection{TenantID: "tenant", DatabaseName: "database", Configuration: invalid}
+	assert.ErrorAs(t, update.Validate(&Collection{TenantID: "tenant", DatabaseName: "database"}), &configErr)
+	update = &UpdateCollection{TenantID: "tenant", DatabaseName: "database", Configuration: config}
+	assert.NoError(t, update.Validate(&Collection{TenantID: "tenant", DatabaseName: "database"}))
+}
+
+func TestDistanceFunction(t *testing.T) {
+	// Test case 1: default fallback
+	collection := &Collection{}
+	assert.Equal(t, "l2", collection.EffectiveDistanceFunction())
+
+	// Test case 2: set value is returned in lowercase
+	collection.DistanceFunction = stringPtr("Cosine")
+	assert.Equal(t, "cosine", collection.EffectiveDistanceFunction())
+
+	// Test case 3: normalization is case-insensitive
+	for _, input := range []string{"l2", "L2", "cosine", "COSINE", "Ip"} {
+		normalized, err := NormalizeDistanceFunction(input)
+		assert.NoError(t, err)
+		assert.Contains(t, []string{SpaceL2, SpaceCosine, SpaceIP}, normalized)
+	}
+	normalized, err := NormalizeDistanceFunction("IP")
+	assert.NoError(t, err)
+	assert.Equal(t, "ip", normalized)
+
+	// Test case 4: invalid metric
+	_, err = NormalizeDistanceFunction("hamming")
+	var distanceErr *InvalidDistanceFunctionError
+	assert.ErrorAs(t, err, &distanceErr)
+	assert.Equal(t, "hamming", distanceErr.DistanceFunction)
+
+	// Test case 5: enforced by CreateCollection.Validate
+	create := &CreateCollection{Name: "collection", DistanceFunction: stringPtr("hamming")}
+	assert.ErrorAs(t, create.Validate(), &distanceErr)
+	create.DistanceFunction = stringPtr("COSINE")
+	assert.False(t, errors.As(create.Validate(), &distanceErr))
+
+	// Test case 6: distance function and space are stored in lowercase
+	upper := newTestCreateCollection("collection", "tenant", "database")
+	upper.DistanceFunction = stringPtr("COSINE")
+	upper.Configuration = &CollectionConfiguration{Space: stringPtr("IP")}
+	assert.NoError(t, upper.Validate())
+	lower := newTestCreateCollection("collection", "tenant", "database")
+	lower.ID = upper.ID
+	lower.DistanceFunction = stringPtr(SpaceCosine)
+	lower.Configuration = &CollectionConfiguration{Space: stringPtr(SpaceIP)}
+	created := NewCollectionFromCreate(upper)
+	assert.Equal(t, SpaceCosine, *created.DistanceFunction)
+	assert.Equal(t, SpaceIP, *created.Configuration.Space)
+	assert.Equal(t, "IP", *upper.Configuration.Space)
+	assert.True(t, created.Equal(NewCollectionFromCreate(lower)))
+	assert.Equal(t, created.Checksum(), NewCollectionFromCreate(lower).Checksum())
+
+	// Test case 7: an updated space is stored in lowercase
+	update := &UpdateCollection{ID: created.ID, TenantID: "tenant", DatabaseName: "database", Configuration: &CollectionConfiguration{Space: stringPtr("L2")}}
+	updated, err := update.Apply(created)
+	assert.NoError(t, err)
+	assert.Equal(t, SpaceL2, *updated.Configuration.Space)
+}
+
+func TestValidateEmbeddingFunction(t *testing.T) {
+	tests := []struct {
+		name              string
+		embeddingFunction *string
+		valid             bool
+	}{
+		{name: "unset", embeddingFunction: nil, valid: true},
+		{name: "named", embeddingFunction: stringPtr("openai-ada-002"), valid: true},
+		{name: "maximum length", embeddingFunction: stringPtr(strings.Repeat("e", MaxEmbeddingFunctionBytes)), valid: true},
+		{name: "empty", embeddingFunction: stringPtr("")},
+		{name: "too long", embeddingFunction: stringPtr(strings.Repeat("e", MaxEmbeddingFunctionBytes+1))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateEmbeddingFunction(tt.embeddingFunction)
+			create := newTestCreateCollection("collection", "tenant", "database")
+			create.EmbeddingFunction = tt.embeddingFunction
+			if tt.valid {
+				assert.NoError(t, err)
+				assert.NoError(t, create.Validate())
+				return
+			}
+			var embeddingErr *InvalidEmbeddingFunctionError
+			assert.ErrorAs(t, err, &embeddingErr)
+			assert.ErrorAs(t, create.Validate(), &embeddingErr)
+		})
+	}
+}
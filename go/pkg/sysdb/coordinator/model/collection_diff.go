@@ -0,0 +1,80 @@
+package model
+
+import "sort"
+
+// CollectionDiff describes what changed between two versions of a
+// collection. Metadata key lists are sorted.
+type CollectionDiff struct {
+	NameChanged      bool
+	DimensionChanged bool
+	MetadataChanged  bool
+	AddedKeys        []string
+	RemovedKeys      []string
+	ModifiedKeys     []string
+}
+
+// DiffCollection compares old and new. A nil collection is treated as having
+// no name, dimension or metadata, so every key of the other side is reported
+// as added or removed. Metadata values are compared by type and value.
+func DiffCollection(old, new *Collection) CollectionDiff {
+	var diff CollectionDiff
+	if old == nil && new == nil {
+		return diff
+	}
+	diff.NameChanged = collectionName(old) != collectionName(new)
+	diff.DimensionChanged = !equalPtr(collectionDimension(old), collectionDimension(new))
+
+	oldMetadata := collectionMetadataMap(old)
+	newMetadata := collectionMetadataMap(new)
+	for key, value := range newMetadata {
+		existing, ok := oldMetadata[key]
+		if !ok {
+			diff.AddedKeys = append(diff.AddedKeys, key)
+		} else if !metadataValuesEqual(existing, value) {
+			diff.ModifiedKeys = append(diff.ModifiedKeys, key)
+		}
+	}
+	for key := range oldMetadata {
+		if _, ok := newMetadata[key]; !ok {
+			diff.RemovedKeys = append(diff.RemovedKeys, key)
+		}
+	}
+	sort.Strings(diff.AddedKeys)
+	sort.Strings(diff.RemovedKeys)
+	sort.Strings(diff.ModifiedKeys)
+	diff.MetadataChanged = len(diff.AddedKeys) > 0 || len(diff.RemovedKeys) > 0 || len(diff.ModifiedKeys) > 0
+	return diff
+}
+
+// HasChanges reports whether any tracked field differs.
+func (d CollectionDiff) HasChanges() bool {
+	return d.NameChanged || d.DimensionChanged || d.MetadataChanged
+}
+
+func collectionName(c *Collection) string {
+	if c == nil {
+		return ""
+	}
+	return c.Name
+}
+
+func collectionDimension(c *Collection) *int32 {
+	if c == nil {
+		return nil
+	}
+	return c.Dimension
+}
+
+func collectionMetadataMap(c *Collection) map[string]CollectionMetadataValueType {
+	if c == nil || c.Metadata == nil {
+		return nil
+	}
+	return c.Metadata.Metadata
+}
+
+func metadataValuesEqual(a, b CollectionMetadataValueType) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equals(b)
+}
@@ -0,0 +1,73 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/chroma-core/chroma/go/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffCollection(t *testing.T) {
+	metadata := NewCollectionMetadata[CollectionMetadataValueType]()
+	metadata.Add("kept", &CollectionMetadataValueStringType{Value: "same"})
+	metadata.Add("removed", &CollectionMetadataValueInt64Type{Value: 1})
+	metadata.Add("retyped", &CollectionMetadataValueInt64Type{Value: 1})
+	metadata.Add("changed", &CollectionMetadataValueFloat64Type{Value: 1.5})
+	old := &Collection{
+		ID:        types.NewUniqueID(),
+		Name:      "old_name",
+		Dimension: int32Ptr(128),
+		Metadata:  metadata,
+	}
+
+	// Test case 1: identical collections
+	diff := DiffCollection(old, old.Clone())
+	assert.False(t, diff.HasChanges())
+	assert.Empty(t, diff.AddedKeys)
+	assert.Empty(t, diff.RemovedKeys)
+	assert.Empty(t, diff.ModifiedKeys)
+
+	// Test case 2: rename only
+	renamed := old.Clone()
+	renamed.Name = "new_name"
+	diff = DiffCollection(old, renamed)
+	assert.True(t, diff.NameChanged)
+	assert.False(t, diff.DimensionChanged)
+	assert.False(t, diff.MetadataChanged)
+
+	// Test case 3: dimension set and changed
+	dimensionless := old.Clone()
+	dimensionless.Dimension = nil
+	assert.True(t, DiffCollection(dimensionless, old).DimensionChanged)
+	resized := old.Clone()
+	resized.Dimension = int32Ptr(256)
+	assert.True(t, DiffCollection(old, resized).DimensionChanged)
+
+	// Test case 4: metadata additions, removals and type-aware modifications
+	updated := old.Clone()
+	updated.Metadata.Remove("removed")
+	updated.Metadata.Add("added", &CollectionMetadataValueBoolType{Value: true})
+	updated.Metadata.Add("retyped", &CollectionMetadataValueStringType{Value: "1"})
+	updated.Metadata.Add("changed", &CollectionMetadataValueFloat64Type{Value: 2.5})
+	diff = DiffCollection(old, updated)
+	assert.True(t, diff.MetadataChanged)
+	assert.False(t, diff.NameChanged)
+	assert.Equal(t, []string{"added"}, diff.AddedKeys)
+	assert.Equal(t, []string{"removed"}, diff.RemovedKeys)
+	assert.Equal(t, []string{"changed", "retyped"}, diff.ModifiedKeys)
+
+	// Test case 5: nil old reports everything as added
+	diff = DiffCollection(nil, old)
+	assert.True(t, diff.NameChanged)
+	assert.True(t, diff.DimensionChanged)
+	assert.Equal(t, []string{"changed", "kept", "removed", "retyped"}, diff.AddedKeys)
+	assert.Empty(t, diff.RemovedKeys)
+
+	// Test case 6: nil new reports everything as removed
+	diff = DiffCollection(old, nil)
+	assert.Equal(t, []string{"changed", "kept", "removed", "retyped"}, diff.RemovedKeys)
+	assert.Empty(t, diff.AddedKeys)
+
+	// Test case 7: both nil
+	assert.False(t, DiffCollection(nil, nil).HasChanges())
+}
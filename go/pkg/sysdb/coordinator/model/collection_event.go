@@ -0,0 +1,53 @@
+package model
+
+import "github.com/chroma-core/chroma/go/pkg/types"
+
+// CollectionEventType identifies the mutation a CollectionEvent records.
+type CollectionEventType string
+
+const (
+	CollectionEventTypeCreate CollectionEventType = "create"
+	CollectionEventTypeUpdate CollectionEventType = "update"
+	CollectionEventTypeDelete CollectionEventType = "delete"
+)
+
+// CollectionEvent is a change-data-capture record of a collection mutation.
+// Before and After are snapshots that do not alias the collections they were
+// built from: Before is nil for creates and After is nil for deletes.
+type CollectionEvent struct {
+	Type         CollectionEventType
+	CollectionID types.UniqueID
+	TenantID     string
+	DatabaseName string
+	Ts           types.Timestamp
+	Before       *Collection
+	After        *Collection
+}
+
+func newCollectionEvent(eventType CollectionEventType, scope *Collection, before *Collection, after *Collection, ts types.Timestamp) *CollectionEvent {
+	return &CollectionEvent{
+		Type:         eventType,
+		CollectionID: scope.ID,
+		TenantID:     scope.TenantID,
+		DatabaseName: scope.DatabaseName,
+		Ts:           ts,
+		Before:       before.Clone(),
+		After:        after.Clone(),
+	}
+}
+
+// NewCreateEvent records the creation of created at ts.
+func NewCreateEvent(created *Collection, ts types.Timestamp) *CollectionEvent {
+	return newCollectionEvent(CollectionEventTypeCreate, created, nil, created, ts)
+}
+
+// NewUpdateEvent records the update of before into after at ts. The event is
+// scoped to after, which reflects a rename or move.
+func NewUpdateEvent(before *Collection, after *Collection, ts types.Timestamp) *CollectionEvent {
+	return newCollectionEvent(CollectionEventTypeUpdate, after, before, after, ts)
+}
+
+// NewDeleteEvent records the deletion of deleted at ts.
+func NewDeleteEvent(deleted *Collection, ts types.Timestamp) *CollectionEvent {
+	return newCollectionEvent(CollectionEventTypeDelete, deleted, deleted, nil, ts)
+}
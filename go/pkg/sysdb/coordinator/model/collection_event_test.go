@@ -0,0 +1,59 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/chroma-core/chroma/go/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectionEvents(t *testing.T) {
+	metadata := NewCollectionMetadata[CollectionMetadataValueType]()
+	metadata.Add("key", &CollectionMetadataValueStringType{Value: "value"})
+	collection := &Collection{
+		ID:           types.NewUniqueID(),
+		Name:         "collection",
+		Metadata:     metadata,
+		TenantID:     "tenant",
+		DatabaseName: "database",
+	}
+
+	// Test case 1: create events have no before snapshot
+	event := NewCreateEvent(collection, 10)
+	assert.Equal(t, CollectionEventTypeCreate, event.Type)
+	assert.Equal(t, collection.ID, event.CollectionID)
+	assert.Equal(t, "tenant", event.TenantID)
+	assert.Equal(t, "database", event.DatabaseName)
+	assert.Equal(t, types.Timestamp(10), event.Ts)
+	assert.Nil(t, event.Before)
+	assert.True(t, collection.Equal(event.After))
+	assert.NotSame(t, collection, event.After)
+
+	// Test case 2: update events capture both snapshots, scoped to after
+	updated := collection.Clone()
+	updated.Name = "renamed"
+	updated.DatabaseName = "other"
+	event = NewUpdateEvent(collection, updated, 11)
+	assert.Equal(t, CollectionEventTypeUpdate, event.Type)
+	assert.Equal(t, "other", event.DatabaseName)
+	assert.Equal(t, "collection", event.Before.Name)
+	assert.Equal(t, "renamed", event.After.Name)
+	assert.NotSame(t, collection, event.Before)
+	assert.NotSame(t, updated, event.After)
+
+	// Test case 3: delete events have no after snapshot
+	event = NewDeleteEvent(collection, 12)
+	assert.Equal(t, CollectionEventTypeDelete, event.Type)
+	assert.Equal(t, collection.ID, event.CollectionID)
+	assert.Equal(t, types.Timestamp(12), event.Ts)
+	assert.True(t, collection.Equal(event.Before))
+	assert.Nil(t, event.After)
+
+	// Test case 4: snapshots do not alias the source collections
+	event = NewCreateEvent(collection, 10)
+	collection.Name = "mutated"
+	collection.Metadata.Add("key", &CollectionMetadataValueStringType{Value: "mutated"})
+	assert.Equal(t, "collection", event.After.Name)
+	value, _ := event.After.Metadata.GetString("key")
+	assert.Equal(t, "value", value)
+}
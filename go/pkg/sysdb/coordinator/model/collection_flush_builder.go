@@ -0,0 +1,79 @@
+package model
+
+import (
+	"github.com/chroma-core/chroma/go/pkg/types"
+)
+
+// FlushCollectionCompactionBuilder assembles a FlushCollectionCompaction and
+// validates it on Build. TotalRecordsCompacted is kept in step with the
+// added segments.
+type FlushCollectionCompactionBuilder struct {
+	flush      FlushCollectionCompaction
+	allowEmpty bool
+}
+
+func NewFlushCollectionCompactionBuilder() *FlushCollectionCompactionBuilder {
+	return &FlushCollectionCompactionBuilder{}
+}
+
+func (b *FlushCollectionCompactionBuilder) WithID(id types.UniqueID) *FlushCollectionCompactionBuilder {
+	b.flush.ID = id
+	return b
+}
+
+func (b *FlushCollectionCompactionBuilder) WithTenant(tenantID string) *FlushCollectionCompactionBuilder {
+	b.flush.TenantID = tenantID
+	return b
+}
+
+func (b *FlushCollectionCompactionBuilder) WithLogPosition(logPosition int64) *FlushCollectionCompactionBuilder {
+	b.flush.LogPosition = logPosition
+	return b
+}
+
+func (b *FlushCollectionCompactionBuilder) WithPreviousLogPosition(previousLogPosition int64) *FlushCollectionCompactionBuilder {
+	b.flush.PreviousLogPosition = previousLogPosition
+	return b
+}
+
+func (b *FlushCollectionCompactionBuilder) WithVersion(version int32) *FlushCollectionCompactionBuilder {
+	b.flush.CurrentCollectionVersion = version
+	return b
+}
+
+func (b *FlushCollectionCompactionBuilder) AddSegment(segment *FlushSegmentCompaction) *FlushCollectionCompactionBuilder {
+	if segment == nil {
+		return b
+	}
+	b.flush.FlushSegmentCompactions = append(b.flush.FlushSegmentCompactions, segment)
+	b.flush.TotalRecordsCompacted += segment.RecordsCompacted
+	return b
+}
+
+// AllowEmpty lets Build succeed without any segments.
+func (b *FlushCollectionCompactionBuilder) AllowEmpty() *FlushCollectionCompactionBuilder {
+	b.allowEmpty = true
+	return b
+}
+
+// Build returns the flush once it has an ID, a tenant and, unless AllowEmpty
+// was called, at least one segment, and passes the same field checks as
+// FlushCollectionCompaction.Validate. The result does not share its segment
+// slice with the builder.
+func (b *FlushCollectionCompactionBuilder) Build() (*FlushCollectionCompaction, error) {
+	if b.flush.ID == types.NilUniqueID() {
+		return nil, &InvalidFlushError{Field: "id", Reason: "is empty"}
+	}
+	if b.flush.TenantID == "" {
+		return nil, &InvalidFlushError{Field: "tenant_id", Reason: "is empty"}
+	}
+	if len(b.flush.FlushSegmentCompactions) == 0 && !b.allowEmpty {
+		return nil, &InvalidFlushError{Field: "flush_segment_compactions", Reason: "is empty"}
+	}
+	flush := b.flush
+	flush.FlushSegmentCompactions = append([]*FlushSegmentCompaction(nil), b.flush.FlushSegmentCompactions...)
+	if err := flush.validateFields(); err != nil {
+		return nil, err
+	}
+	return &flush, nil
+}
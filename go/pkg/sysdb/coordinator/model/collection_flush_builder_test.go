@@ -0,0 +1,67 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/chroma-core/chroma/go/pkg/common"
+	"github.com/chroma-core/chroma/go/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlushCollectionCompactionBuilder(t *testing.T) {
+	id := types.NewUniqueID()
+	segment := &FlushSegmentCompaction{
+		ID:               types.NewUniqueID(),
+		FilePaths:        map[string][]string{"hnsw_index": {"a"}},
+		RecordsCompacted: 7,
+	}
+
+	// Test case 1: valid build
+	builder := NewFlushCollectionCompactionBuilder().
+		WithID(id).
+		WithTenant("tenant").
+		WithPreviousLogPosition(5).
+		WithLogPosition(10).
+		WithVersion(2).
+		AddSegment(segment)
+	flush, err := builder.Build()
+	assert.NoError(t, err)
+	assert.Equal(t, &FlushCollectionCompaction{
+		ID:                       id,
+		TenantID:                 "tenant",
+		LogPosition:              10,
+		PreviousLogPosition:      5,
+		CurrentCollectionVersion: 2,
+		FlushSegmentCompactions:  []*FlushSegmentCompaction{segment},
+		TotalRecordsCompacted:    7,
+	}, flush)
+	builder.AddSegment(&FlushSegmentCompaction{ID: types.NewUniqueID()})
+	assert.Len(t, flush.FlushSegmentCompactions, 1)
+
+	// Test case 2: missing required fields
+	_, err = NewFlushCollectionCompactionBuilder().WithTenant("tenant").AddSegment(segment).Build()
+	var invalid *InvalidFlushError
+	assert.ErrorAs(t, err, &invalid)
+	assert.Equal(t, "id", invalid.Field)
+	_, err = NewFlushCollectionCompactionBuilder().WithID(id).AddSegment(segment).Build()
+	assert.ErrorAs(t, err, &invalid)
+	assert.Equal(t, "tenant_id", invalid.Field)
+	assert.ErrorIs(t, err, common.ErrInvalidFlush)
+
+	// Test case 3: no segments without AllowEmpty
+	_, err = NewFlushCollectionCompactionBuilder().WithID(id).WithTenant("tenant").Build()
+	assert.ErrorAs(t, err, &invalid)
+	assert.Equal(t, "flush_segment_compactions", invalid.Field)
+	flush, err = NewFlushCollectionCompactionBuilder().WithID(id).WithTenant("tenant").AllowEmpty().Build()
+	assert.NoError(t, err)
+	assert.Empty(t, flush.FlushSegmentCompactions)
+
+	// Test case 4: field checks run on build
+	_, err = NewFlushCollectionCompactionBuilder().WithID(id).WithTenant("tenant").
+		WithPreviousLogPosition(10).WithLogPosition(5).AddSegment(segment).Build()
+	assert.ErrorAs(t, err, &invalid)
+	assert.Equal(t, "previous_log_position", invalid.Field)
+	_, err = NewFlushCollectionCompactionBuilder().WithID(id).WithTenant("tenant").
+		AddSegment(&FlushSegmentCompaction{}).Build()
+	assert.ErrorIs(t, err, common.ErrMissingSegmentID)
+}
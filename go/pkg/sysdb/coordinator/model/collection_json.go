@@ -0,0 +1,292 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/chroma-core/chroma/go/pkg/common"
+	"github.com/chroma-core/chroma/go/pkg/types"
+)
+
+const (
+	metadataValueJSONTypeString = "string"
+	metadataValueJSONTypeInt    = "int"
+	metadataValueJSONTypeFloat  = "float"
+	metadataValueJSONTypeBool   = "bool"
+	metadataValueJSONTypeList   = "string_list"
+	metadataValueJSONTypeNone   = "none"
+)
+
+// collectionJSON is the wire format of a Collection. IDs are encoded as
+// canonical UUID strings and timestamps as integers. Metadata values carry
+// their type so that ints and floats survive a round trip unchanged.
+type collectionJSON struct {
+	ID                   string                                  `json:"id"`
+	Name                 string                                  `json:"name"`
+	ConfigurationJsonStr string                                  `json:"configuration_json_str"`
+	Configuration        *collectionConfigurationJSON            `json:"configuration,omitempty"`
+	DistanceFunction     *string                                 `json:"distance_function,omitempty"`
+	EmbeddingFunction    *string                                 `json:"embedding_function,omitempty"`
+	Dimension            *int32                                  `json:"dimension,omitempty"`
+	Metadata             *map[string]collectionMetadataValueJSON `json:"metadata,omitempty"`
+	Labels               map[string]string                       `json:"labels,omitempty"`
+	TenantID             string                                  `json:"tenant_id"`
+	DatabaseName         string                                  `json:"database_name"`
+	Ts                   types.Timestamp                         `json:"ts"`
+	LogPosition          int64                                   `json:"log_position"`
+	LastCompactionTime   int64                                   `json:"last_compaction_time"`
+	Version              int32                                   `json:"version"`
+	UpdateVersion        int32                                   `json:"update_version"`
+	DeletedAt            *types.Timestamp                        `json:"deleted_at,omitempty"`
+	ExpiresAt            *types.Timestamp                        `json:"expires_at,omitempty"`
+	State                CollectionState                         `json:"state"`
+	SourceCollectionID   *string                                 `json:"source_collection_id,omitempty"`
+	ForkedAt             *types.Timestamp                        `json:"forked_at,omitempty"`
+	ReadOnly             bool                                    `json:"read_only"`
+	Stats                *collectionStatsJSON                    `json:"stats,omitempty"`
+	CreatedBy            string                                  `json:"created_by,omitempty"`
+	UpdatedBy            string                                  `json:"updated_by,omitempty"`
+	RequiresReindex      bool                                    `json:"requires_reindex"`
+}
+
+type collectionStatsJSON struct {
+	RecordCount      uint64 `json:"record_count"`
+	SegmentCount     uint32 `json:"segment_count"`
+	LogicalSizeBytes uint64 `json:"logical_size_bytes"`
+}
+
+type collectionConfigurationJSON struct {
+	HnswM              *int32  `json:"hnsw_m,omitempty"`
+	HnswConstructionEf *int32  `json:"hnsw_construction_ef,omitempty"`
+	HnswSearchEf       *int32  `json:"hnsw_search_ef,omitempty"`
+	Space              *string `json:"space,omitempty"`
+}
+
+type collectionMetadataValueJSON struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+func marshalCollectionMetadataValue(value CollectionMetadataValueType) (collectionMetadataValueJSON, error) {
+	var valueType string
+	var raw interface{}
+	switch v := value.(type) {
+	case *CollectionMetadataValueStringType:
+		valueType, raw = metadataValueJSONTypeString, v.Value
+	case *CollectionMetadataValueInt64Type:
+		valueType, raw = metadataValueJSONTypeInt, v.Value
+	case *CollectionMetadataValueFloat64Type:
+		valueType, raw = metadataValueJSONTypeFloat, v.Value
+	case *CollectionMetadataValueBoolType:
+		valueType, raw = metadataValueJSONTypeBool, v.Value
+	case *CollectionMetadataValueStringListType:
+		valueType, raw = metadataValueJSONTypeList, v.Value
+	case *CollectionMetadataValueNoneType:
+		valueType, raw = metadataValueJSONTypeNone, nil
+	default:
+		return collectionMetadataValueJSON{}, common.ErrUnknownCollectionMetadataType
+	}
+	encoded, err := json.Marshal(raw)
+	if err != nil {
+		return collectionMetadataValueJSON{}, err
+	}
+	return collectionMetadataValueJSON{Type: valueType, Value: encoded}, nil
+}
+
+func unmarshalCollectionMetadataValue(value collectionMetadataValueJSON) (CollectionMetadataValueType, error) {
+	switch value.Type {
+	case metadataValueJSONTypeString:
+		v := &CollectionMetadataValueStringType{}
+		return v, json.Unmarshal(value.Value, &v.Value)
+	case metadataValueJSONTypeInt:
+		v := &CollectionMetadataValueInt64Type{}
+		return v, json.Unmarshal(value.Value, &v.Value)
+	case metadataValueJSONTypeFloat:
+		v := &CollectionMetadataValueFloat64Type{}
+		return v, json.Unmarshal(value.Value, &v.Value)
+	case metadataValueJSONTypeBool:
+		v := &CollectionMetadataValueBoolType{}
+		return v, json.Unmarshal(value.Value, &v.Value)
+	case metadataValueJSONTypeList:
+		v := &CollectionMetadataValueStringListType{}
+		return v, json.Unmarshal(value.Value, &v.Value)
+	case metadataValueJSONTypeNone:
+		return &CollectionMetadataValueNoneType{}, nil
+	default:
+		return nil, common.ErrUnknownCollectionMetadataType
+	}
+}
+
+// ParseMetadataJSON decodes a JSON object such as `{"k":1}` into typed
+// metadata. Integers become int values, other numbers float values, arrays of
+// strings string lists, and null a none value. Other arrays and nested objects
+// return an *UnsupportedMetadataValueError; input that is not a single JSON
+// object, including a bare null, returns a *MalformedMetadataJSONError. An
+// empty string or `{}` yields nil metadata.
+func ParseMetadataJSON(s string) (*CollectionMetadata[CollectionMetadataValueType], error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	decoder := json.NewDecoder(strings.NewReader(s))
+	decoder.UseNumber()
+	var decoded map[string]interface{}
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, &MalformedMetadataJSONError{Err: err}
+	}
+	if decoded == nil {
+		return nil, &MalformedMetadataJSONError{Err: fmt.Errorf("metadata must be a JSON object")}
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, &MalformedMetadataJSONError{Err: fmt.Errorf("unexpected data after the metadata object")}
+	}
+	for key, value := range decoded {
+		if number, ok := value.(json.Number); ok {
+			decoded[key] = metadataNumber(number)
+		}
+	}
+	return CollectionMetadataFromMap(decoded)
+}
+
+func metadataNumber(number json.Number) interface{} {
+	if !strings.ContainsAny(string(number), ".eE") {
+		if i, err := number.Int64(); err == nil {
+			return i
+		}
+	}
+	if f, err := number.Float64(); err == nil {
+		return f
+	}
+	return number
+}
+
+// MarshalJSON has a value receiver so that Collection values, not only
+// pointers, use the snake_case encoding.
+func (c Collection) MarshalJSON() ([]byte, error) {
+	out := collectionJSON{
+		ID:                   c.ID.String(),
+		Name:                 c.Name,
+		ConfigurationJsonStr: c.ConfigurationJsonStr,
+		DistanceFunction:     c.DistanceFunction,
+		EmbeddingFunction:    c.EmbeddingFunction,
+		Dimension:            c.Dimension,
+		Labels:               c.Labels,
+		TenantID:             c.TenantID,
+		DatabaseName:         c.DatabaseName,
+		Ts:                   c.Ts,
+		LogPosition:          c.LogPosition,
+		LastCompactionTime:   c.LastCompactionTime,
+		Version:              c.Version,
+		UpdateVersion:        c.UpdateVersion,
+		DeletedAt:            c.DeletedAt,
+		ExpiresAt:            c.ExpiresAt,
+		State:                c.State,
+		ForkedAt:             c.ForkedAt,
+		ReadOnly:             c.ReadOnly,
+		CreatedBy:            c.CreatedBy,
+		UpdatedBy:            c.UpdatedBy,
+		RequiresReindex:      c.RequiresReindex,
+	}
+	if c.Stats != nil {
+		out.Stats = &collectionStatsJSON{
+			RecordCount:      c.Stats.RecordCount,
+			SegmentCount:     c.Stats.SegmentCount,
+			LogicalSizeBytes: c.Stats.LogicalSizeBytes,
+		}
+	}
+	if c.SourceCollectionID != nil {
+		sourceID := c.SourceCollectionID.String()
+		out.SourceCollectionID = &sourceID
+	}
+	if c.Configuration != nil {
+		out.Configuration = &collectionConfigurationJSON{
+			HnswM:              c.Configuration.HnswM,
+			HnswConstructionEf: c.Configuration.HnswConstructionEf,
+			HnswSearchEf:       c.Configuration.HnswSearchEf,
+			Space:              c.Configuration.Space,
+		}
+	}
+	if c.Metadata != nil {
+		metadata := make(map[string]collectionMetadataValueJSON, len(c.Metadata.Metadata))
+		for _, key := range c.Metadata.SortedKeys() {
+			encoded, err := marshalCollectionMetadataValue(c.Metadata.Metadata[key])
+			if err != nil {
+				return nil, fmt.Errorf("metadata key %q: %w", key, err)
+			}
+			metadata[key] = encoded
+		}
+		out.Metadata = &metadata
+	}
+	return json.Marshal(out)
+}
+
+func (c *Collection) UnmarshalJSON(data []byte) error {
+	var in collectionJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	id, err := types.Parse(in.ID)
+	if err != nil {
+		return common.ErrCollectionIDFormat
+	}
+	collection := Collection{
+		ID:                   id,
+		Name:                 in.Name,
+		ConfigurationJsonStr: in.ConfigurationJsonStr,
+		DistanceFunction:     in.DistanceFunction,
+		EmbeddingFunction:    in.EmbeddingFunction,
+		Dimension:            in.Dimension,
+		Labels:               in.Labels,
+		TenantID:             in.TenantID,
+		DatabaseName:         in.DatabaseName,
+		Ts:                   in.Ts,
+		LogPosition:          in.LogPosition,
+		LastCompactionTime:   in.LastCompactionTime,
+		Version:              in.Version,
+		UpdateVersion:        in.UpdateVersion,
+		DeletedAt:            in.DeletedAt,
+		ExpiresAt:            in.ExpiresAt,
+		State:                in.State,
+		ForkedAt:             in.ForkedAt,
+		ReadOnly:             in.ReadOnly,
+		CreatedBy:            in.CreatedBy,
+		UpdatedBy:            in.UpdatedBy,
+		RequiresReindex:      in.RequiresReindex,
+	}
+	if in.Stats != nil {
+		collection.Stats = &CollectionStats{
+			RecordCount:      in.Stats.RecordCount,
+			SegmentCount:     in.Stats.SegmentCount,
+			LogicalSizeBytes: in.Stats.LogicalSizeBytes,
+		}
+	}
+	if in.SourceCollectionID != nil {
+		sourceID, err := types.Parse(*in.SourceCollectionID)
+		if err != nil {
+			return common.ErrCollectionIDFormat
+		}
+		collection.SourceCollectionID = &sourceID
+	}
+	if in.Configuration != nil {
+		collection.Configuration = &CollectionConfiguration{
+			HnswM:              in.Configuration.HnswM,
+			HnswConstructionEf: in.Configuration.HnswConstructionEf,
+			HnswSearchEf:       in.Configuration.HnswSearchEf,
+			Space:              in.Configuration.Space,
+		}
+	}
+	if in.Metadata != nil {
+		metadata := NewCollectionMetadata[CollectionMetadataValueType]()
+		for key, value := range *in.Metadata {
+			decoded, err := unmarshalCollectionMetadataValue(value)
+			if err != nil {
+				return fmt.Errorf("metadata key %q: %w", key, err)
+			}
+			metadata.Add(key, decoded)
+		}
+		collection.Metadata = metadata
+	}
+	*c = collection
+	return nil
+}
@@ -0,0 +1,182 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/chroma-core/chroma/go/pkg/common"
+	"github.com/chroma-core/chroma/go/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectionJSONKeys(t *testing.T) {
+	dimension := int32(3)
+	metadata := NewCollectionMetadata[CollectionMetadataValueType]()
+	metadata.Add("key", &CollectionMetadataValueStringType{Value: "value"})
+	collection := &Collection{
+		ID:           types.MustParse("00000000-0000-0000-0000-000000000001"),
+		Name:         "collection",
+		Dimension:    &dimension,
+		Metadata:     metadata,
+		TenantID:     "tenant",
+		DatabaseName: "database",
+		Ts:           7,
+	}
+	collection.EmbeddingFunction = stringPtr("openai-ada-002")
+	data, err := json.Marshal(collection)
+	assert.NoError(t, err)
+
+	var raw map[string]interface{}
+	assert.NoError(t, json.Unmarshal(data, &raw))
+	assert.Equal(t, "00000000-0000-0000-0000-000000000001", raw["id"])
+	assert.Equal(t, "collection", raw["name"])
+	assert.Equal(t, float64(3), raw["dimension"])
+	assert.Equal(t, "tenant", raw["tenant_id"])
+	assert.Equal(t, "database", raw["database_name"])
+	assert.Equal(t, float64(7), raw["ts"])
+	assert.Equal(t, "openai-ada-002", raw["embedding_function"])
+	assert.Equal(t, map[string]interface{}{
+		"key": map[string]interface{}{"type": "string", "value": "value"},
+	}, raw["metadata"])
+
+	// nil pointers are omitted
+	data, err = json.Marshal(&Collection{ID: types.NewUniqueID()})
+	assert.NoError(t, err)
+	raw = nil
+	assert.NoError(t, json.Unmarshal(data, &raw))
+	assert.NotContains(t, raw, "dimension")
+	assert.NotContains(t, raw, "metadata")
+	assert.NotContains(t, raw, "deleted_at")
+	assert.NotContains(t, raw, "configuration")
+	assert.NotContains(t, raw, "labels")
+	assert.NotContains(t, raw, "stats")
+	assert.NotContains(t, raw, "embedding_function")
+	assert.NotContains(t, raw, "source_collection_id")
+}
+
+func TestCollectionJSONRoundTrip(t *testing.T) {
+	dimension := int32(128)
+	deletedAt := types.Timestamp(99)
+	mixed := NewCollectionMetadata[CollectionMetadataValueType]()
+	mixed.Add("string", &CollectionMetadataValueStringType{Value: "value"})
+	mixed.Add("int", &CollectionMetadataValueInt64Type{Value: 9007199254740993})
+	mixed.Add("float", &CollectionMetadataValueFloat64Type{Value: 3})
+	mixed.Add("bool", &CollectionMetadataValueBoolType{Value: false})
+	mixed.Add("list", &CollectionMetadataValueStringListType{Value: []string{"ml", "prod"}})
+	mixed.Add("empty_list", &CollectionMetadataValueStringListType{Value: []string{}})
+	mixed.Add("none", &CollectionMetadataValueNoneType{})
+
+	collections := []*Collection{
+		{},
+		{ID: types.NewUniqueID(), Name: "nil pointers", TenantID: "tenant", DatabaseName: "database", Ts: 1},
+		{ID: types.NewUniqueID(), Name: "dimension only", Dimension: &dimension},
+		{ID: types.NewUniqueID(), Name: "empty metadata", Metadata: NewCollectionMetadata[CollectionMetadataValueType]()},
+		{ID: types.NewUniqueID(), Name: "default configuration", Configuration: &CollectionConfiguration{}},
+		{
+			ID:                   types.NewUniqueID(),
+			Name:                 "everything",
+			ConfigurationJsonStr: `{"a":1}`,
+			Configuration: &CollectionConfiguration{
+				HnswM:              int32Ptr(16),
+				HnswConstructionEf: int32Ptr(100),
+				HnswSearchEf:       int32Ptr(10),
+				Space:              stringPtr(SpaceCosine),
+			},
+			DistanceFunction:   stringPtr(SpaceIP),
+			EmbeddingFunction:  stringPtr("openai-ada-002"),
+			Dimension:          &dimension,
+			Metadata:           mixed,
+			Labels:             map[string]string{"env": "prod"},
+			TenantID:           "tenant",
+			DatabaseName:       "database",
+			Ts:                 types.MaxTimestamp,
+			LogPosition:        10,
+			LastCompactionTime: 1700000000,
+			Version:            2,
+			UpdateVersion:      4,
+			DeletedAt:          &deletedAt,
+			ExpiresAt:          &deletedAt,
+			State:              CollectionStateDeleting,
+			SourceCollectionID: func() *types.UniqueID {
+				id := types.NewUniqueID()
+				return &id
+			}(),
+			ForkedAt:        &deletedAt,
+			ReadOnly:        true,
+			Stats:           &CollectionStats{RecordCount: 1000, SegmentCount: 3, LogicalSizeBytes: 512000},
+			CreatedBy:       "alice",
+			UpdatedBy:       "bob",
+			RequiresReindex: true,
+		},
+	}
+	for _, collection := range collections {
+		data, err := json.Marshal(collection)
+		assert.NoError(t, err)
+		decoded := &Collection{}
+		assert.NoError(t, json.Unmarshal(data, decoded))
+		assert.Equal(t, collection, decoded, collection.Name)
+
+		// Marshaling a value uses the same encoding as a pointer.
+		valueData, err := json.Marshal(*collection)
+		assert.NoError(t, err)
+		assert.JSONEq(t, string(data), string(valueData), collection.Name)
+		decoded = &Collection{}
+		assert.NoError(t, json.Unmarshal(valueData, decoded))
+		assert.Equal(t, collection, decoded, collection.Name)
+	}
+
+	// A value nested in another struct is encoded with snake_case keys.
+	id := types.NewUniqueID()
+	data, err := json.Marshal(struct{ Collection Collection }{Collection{ID: id, Name: "nested"}})
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"id":"`+id.String()+`"`)
+	assert.Contains(t, string(data), `"name":"nested"`)
+}
+
+func TestCollectionJSONUnmarshalErrors(t *testing.T) {
+	collection := &Collection{}
+	assert.Error(t, json.Unmarshal([]byte(`{"id":"not-a-uuid"}`), collection))
+	assert.Error(t, json.Unmarshal([]byte(`{"id":"00000000-0000-0000-0000-000000000001","source_collection_id":"not-a-uuid"}`), collection))
+	assert.Error(t, json.Unmarshal([]byte(`{"id":"00000000-0000-0000-0000-000000000001","metadata":{"k":{"type":"unknown","value":1}}}`), collection))
+	assert.Error(t, json.Unmarshal([]byte(`{"id":"00000000-0000-0000-0000-000000000001","metadata":{"k":{"type":"int","value":"1"}}}`), collection))
+}
+
+func TestParseMetadataJSON(t *testing.T) {
+	// Test case 1: each value type
+	metadata, err := ParseMetadataJSON(`{"string":"1","int":1,"big":9007199254740993,"float":1.5,"exp":1e3,"bool":true,"list":["a","b"],"none":null}`)
+	assert.NoError(t, err)
+	expected := NewCollectionMetadata[CollectionMetadataValueType]()
+	expected.Add("string", &CollectionMetadataValueStringType{Value: "1"})
+	expected.Add("int", &CollectionMetadataValueInt64Type{Value: 1})
+	expected.Add("big", &CollectionMetadataValueInt64Type{Value: 9007199254740993})
+	expected.Add("float", &CollectionMetadataValueFloat64Type{Value: 1.5})
+	expected.Add("exp", &CollectionMetadataValueFloat64Type{Value: 1000})
+	expected.Add("bool", &CollectionMetadataValueBoolType{Value: true})
+	expected.Add("list", &CollectionMetadataValueStringListType{Value: []string{"a", "b"}})
+	expected.Add("none", &CollectionMetadataValueNoneType{})
+	assert.Equal(t, expected, metadata)
+
+	// Test case 2: the empty cases yield nil metadata
+	for _, input := range []string{"", "  ", "{}", " { } "} {
+		metadata, err := ParseMetadataJSON(input)
+		assert.NoError(t, err, input)
+		assert.Nil(t, metadata, input)
+	}
+
+	// Test case 3: malformed JSON
+	for _, input := range []string{`{"k":`, `{"k":1} {"k":2}`, `[1]`, `"k"`, `{k:1}`, `null`, ` null `} {
+		_, err := ParseMetadataJSON(input)
+		assert.ErrorIs(t, err, common.ErrMalformedMetadataJSON, input)
+		var malformedErr *MalformedMetadataJSONError
+		assert.ErrorAs(t, err, &malformedErr, input)
+	}
+
+	// Test case 4: unsupported values
+	for _, input := range []string{`{"k":{"nested":1}}`, `{"k":[1,2]}`, `{"k":["a",1]}`} {
+		_, err := ParseMetadataJSON(input)
+		assert.ErrorIs(t, err, common.ErrUnknownCollectionMetadataType, input)
+		var unsupportedErr *UnsupportedMetadataValueError
+		assert.ErrorAs(t, err, &unsupportedErr, input)
+		assert.Equal(t, "k", unsupportedErr.Key)
+	}
+}
@@ -0,0 +1,87 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+)
+
+const (
+	MaxLabels           = 16
+	MaxLabelKeyLength   = 63
+	MaxLabelValueLength = 63
+)
+
+// ValidateLabels checks labels against the label limits: at most MaxLabels
+// labels, non-empty keys from [a-z0-9_-] and keys and values no longer than
+// 63 characters. Keys are checked in sorted order so the reported error is
+// deterministic.
+func ValidateLabels(labels map[string]string) error {
+	if len(labels) > MaxLabels {
+		return &InvalidLabelError{Reason: fmt.Sprintf("at most %d labels are allowed, got %d", MaxLabels, len(labels))}
+	}
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if key == "" {
+			return &InvalidLabelError{Key: key, Reason: "key is empty"}
+		}
+		if len(key) > MaxLabelKeyLength {
+			return &InvalidLabelError{Key: key, Reason: fmt.Sprintf("key is longer than %d characters", MaxLabelKeyLength)}
+		}
+		for _, r := range key {
+			if !isLabelKeyRune(r) {
+				return &InvalidLabelError{Key: key, Reason: fmt.Sprintf("key contains illegal character %q", r)}
+			}
+		}
+		if len(labels[key]) > MaxLabelValueLength {
+			return &InvalidLabelError{Key: key, Reason: fmt.Sprintf("value is longer than %d characters", MaxLabelValueLength)}
+		}
+	}
+	return nil
+}
+
+func isLabelKeyRune(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' || r == '-'
+}
+
+// FilterCollectionByLabels matches collections carrying every label in
+// selector with the same value. An empty selector matches all collections; a
+// nil collection never matches.
+func FilterCollectionByLabels(collection *Collection, selector map[string]string) bool {
+	if collection == nil {
+		return false
+	}
+	for key, value := range selector {
+		existing, ok := collection.Labels[key]
+		if !ok || existing != value {
+			return false
+		}
+	}
+	return true
+}
+
+func cloneLabels(labels map[string]string) map[string]string {
+	if labels == nil {
+		return nil
+	}
+	clone := make(map[string]string, len(labels))
+	for key, value := range labels {
+		clone[key] = value
+	}
+	return clone
+}
+
+func equalLabels(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key, value := range a {
+		if other, ok := b[key]; !ok || other != value {
+			return false
+		}
+	}
+	return true
+}
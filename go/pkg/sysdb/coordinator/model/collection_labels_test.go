@@ -0,0 +1,75 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/chroma-core/chroma/go/pkg/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateLabels(t *testing.T) {
+	tooMany := make(map[string]string, MaxLabels+1)
+	for i := 0; i <= MaxLabels; i++ {
+		tooMany[fmt.Sprintf("key_%d", i)] = "value"
+	}
+	maxLabels := make(map[string]string, MaxLabels)
+	for i := 0; i < MaxLabels; i++ {
+		maxLabels[fmt.Sprintf("key_%d", i)] = "value"
+	}
+	tests := []struct {
+		name   string
+		labels map[string]string
+		valid  bool
+	}{
+		{name: "nil", labels: nil, valid: true},
+		{name: "valid", labels: map[string]string{"env": "prod", "team-1_a": "Search Team"}, valid: true},
+		{name: "maximum labels", labels: maxLabels, valid: true},
+		{name: "maximum lengths", labels: map[string]string{strings.Repeat("k", 63): strings.Repeat("v", 63)}, valid: true},
+		{name: "too many labels", labels: tooMany},
+		{name: "empty key", labels: map[string]string{"": "value"}},
+		{name: "key too long", labels: map[string]string{strings.Repeat("k", 64): "value"}},
+		{name: "value too long", labels: map[string]string{"env": strings.Repeat("v", 64)}},
+		{name: "uppercase key", labels: map[string]string{"Env": "prod"}},
+		{name: "illegal key character", labels: map[string]string{"env.name": "prod"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateLabels(tt.labels)
+			if tt.valid {
+				assert.NoError(t, err)
+				return
+			}
+			var labelErr *InvalidLabelError
+			assert.ErrorAs(t, err, &labelErr)
+			assert.ErrorIs(t, err, common.ErrInvalidLabel)
+		})
+	}
+
+	// Labels are checked by CreateCollection and UpdateCollection validation.
+	create := newTestCreateCollection("collection", "tenant", "database")
+	create.Labels = map[string]string{"Env": "prod"}
+	assert.ErrorIs(t, create.Validate(), common.ErrInvalidLabel)
+	update := &UpdateCollection{TenantID: "tenant", DatabaseName: "database", Labels: map[string]string{"Env": "prod"}}
+	assert.ErrorIs(t, update.Validate(&Collection{}), common.ErrInvalidLabel)
+}
+
+func TestFilterCollectionByLabels(t *testing.T) {
+	collection := &Collection{Labels: map[string]string{"env": "prod", "team": "search"}}
+
+	// Test case 1: empty selector matches everything
+	assert.True(t, FilterCollectionByLabels(collection, nil))
+	assert.True(t, FilterCollectionByLabels(&Collection{}, map[string]string{}))
+
+	// Test case 2: all selector labels match
+	assert.True(t, FilterCollectionByLabels(collection, map[string]string{"env": "prod"}))
+	assert.True(t, FilterCollectionByLabels(collection, map[string]string{"env": "prod", "team": "search"}))
+
+	// Test case 3: partial match is a miss
+	assert.False(t, FilterCollectionByLabels(collection, map[string]string{"env": "prod", "team": "ads"}))
+	assert.False(t, FilterCollectionByLabels(collection, map[string]string{"env": "prod", "region": "us"}))
+
+	// Test case 4: collection without labels
+	assert.False(t, FilterCollectionByLabels(&Collection{}, map[string]string{"env": "prod"}))
+}
@@ -0,0 +1,206 @@
+package model
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+
+	"github.com/chroma-core/chroma/go/pkg/types"
+)
+
+type CollectionSortKey int
+
+const (
+	SortByName CollectionSortKey = iota
+	SortByCreatedTs
+	SortByID
+	SortByRecordCount
+)
+
+// CollectionListOptions selects and pages through a list of collections.
+// Unset filters match every collection and a Limit of 0 means no limit.
+// CreatedAfter and CreatedBefore bound Ts to the window [CreatedAfter,
+// CreatedBefore); a nil bound leaves that side open.
+type CollectionListOptions struct {
+	ID             types.UniqueID
+	IDs            []types.UniqueID
+	Name           *string
+	NameMatch      *NameMatch
+	TenantID       *string
+	DatabaseName   *string
+	IncludeDeleted bool
+	ReadyOnly      bool
+	CreatedAfter   *types.Timestamp
+	CreatedBefore  *types.Timestamp
+	SortBy         CollectionSortKey
+	Descending     bool
+	Limit          int
+	Offset         int
+}
+
+// Validate reports invalid options, such as a bad name pattern or a
+// CreatedAfter later than CreatedBefore, before any collection is filtered.
+func (o CollectionListOptions) Validate() error {
+	if err := o.NameMatch.Validate(); err != nil {
+		return err
+	}
+	if o.CreatedAfter != nil && o.CreatedBefore != nil && *o.CreatedAfter > *o.CreatedBefore {
+		return &InvalidTimeWindowError{After: *o.CreatedAfter, Before: *o.CreatedBefore}
+	}
+	return nil
+}
+
+// matches reports whether collection passes the filters in o. ids is the set
+// form of o.IDs, built once per list; nil means no ID set filter.
+func (o CollectionListOptions) matches(collection *Collection, ids *types.UniqueIDSet) bool {
+	if collection == nil {
+		return false
+	}
+	if !FilterCollectionByIDSet(collection, ids) {
+		return false
+	}
+	if o.CreatedAfter != nil && collection.Ts < *o.CreatedAfter {
+		return false
+	}
+	if o.CreatedBefore != nil && collection.Ts >= *o.CreatedBefore {
+		return false
+	}
+	var filterOptions []CollectionFilterOption
+	if o.IncludeDeleted {
+		filterOptions = append(filterOptions, WithIncludeDeleted())
+	}
+	if o.ReadyOnly {
+		filterOptions = append(filterOptions, WithReadyOnly())
+	}
+	if o.NameMatch != nil {
+		filterOptions = append(filterOptions, WithNameMatch(o.NameMatch))
+	}
+	return FilterCollection(collection, o.ID, o.Name, filterOptions...) &&
+		FilterCollectionByScope(collection, o.TenantID, o.DatabaseName)
+}
+
+func compareCollectionIDs(a, b *Collection) int {
+	return bytes.Compare(a.ID[:], b.ID[:])
+}
+
+func compareCollections(a, b *Collection, sortBy CollectionSortKey) int {
+	switch sortBy {
+	case SortByCreatedTs:
+		if a.Ts < b.Ts {
+			return -1
+		}
+		if a.Ts > b.Ts {
+			return 1
+		}
+		return 0
+	case SortByID:
+		return compareCollectionIDs(a, b)
+	case SortByRecordCount:
+		if a.RecordCount() < b.RecordCount() {
+			return -1
+		}
+		if a.RecordCount() > b.RecordCount() {
+			return 1
+		}
+		return 0
+	default:
+		return strings.Compare(a.Name, b.Name)
+	}
+}
+
+// FilterCollections returns the collections matching opts ordered by SortBy,
+// with ties broken by ID, then applies Offset and Limit. opts is validated
+// first, which compiles a regex NameMatch; concurrent calls must share only
+// options that were already validated. The input slice is not modified.
+func FilterCollections(collections []*Collection, opts CollectionListOptions) ([]*Collection, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	var ids *types.UniqueIDSet
+	if len(opts.IDs) > 0 {
+		ids = types.NewUniqueIDSet(opts.IDs...)
+	}
+	result := make([]*Collection, 0, len(collections))
+	for _, collection := range collections {
+		if opts.matches(collection, ids) {
+			result = append(result, collection)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		cmp := compareCollections(result[i], result[j], opts.SortBy)
+		if opts.Descending {
+			cmp = -cmp
+		}
+		if cmp == 0 {
+			return compareCollectionIDs(result[i], result[j]) < 0
+		}
+		return cmp < 0
+	})
+
+	if opts.Offset > 0 {
+		if opts.Offset >= len(result) {
+			return []*Collection{}, nil
+		}
+		result = result[opts.Offset:]
+	}
+	if opts.Limit > 0 && opts.Limit < len(result) {
+		result = result[:opts.Limit]
+	}
+	return result, nil
+}
+
+// ExistsByName reports whether a collection that is not soft deleted has name
+// in the given tenant and database. Nil entries are skipped.
+func ExistsByName(collections []*Collection, name, tenantID, databaseName string) bool {
+	opts := CollectionListOptions{Name: &name, TenantID: &tenantID, DatabaseName: &databaseName}
+	for _, collection := range collections {
+		if opts.matches(collection, nil) {
+			return true
+		}
+	}
+	return false
+}
+
+// CountMatching returns how many collections FilterCollections would match
+// with opts before Offset and Limit are applied. Nil entries never match.
+// Invalid options are reported as FilterCollections reports them.
+func CountMatching(collections []*Collection, opts CollectionListOptions) (int, error) {
+	if err := opts.Validate(); err != nil {
+		return 0, err
+	}
+	var ids *types.UniqueIDSet
+	if len(opts.IDs) > 0 {
+		ids = types.NewUniqueIDSet(opts.IDs...)
+	}
+	count := 0
+	for _, collection := range collections {
+		if opts.matches(collection, ids) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// SortByCompactionStaleness sorts collections in place so the most stale come
+// first: never compacted collections, then by ascending LastCompactionTime.
+// Ties are broken by Ts, oldest first, then by ID. Nil collections sort last.
+// The order does not depend on now, so compaction times after now (clock
+// skew) still sort by their LastCompactionTime rather than as equally fresh.
+func SortByCompactionStaleness(collections []*Collection, now int64) {
+	sort.SliceStable(collections, func(i, j int) bool {
+		a, b := collections[i], collections[j]
+		if a == nil || b == nil {
+			return a != nil
+		}
+		if aNever, bNever := a.LastCompactionTime == 0, b.LastCompactionTime == 0; aNever != bNever {
+			return aNever
+		}
+		if a.LastCompactionTime != b.LastCompactionTime {
+			return a.LastCompactionTime < b.LastCompactionTime
+		}
+		if a.Ts != b.Ts {
+			return a.Ts < b.Ts
+		}
+		return compareCollectionIDs(a, b) < 0
+	})
+}
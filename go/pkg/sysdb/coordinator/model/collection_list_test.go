@@ -0,0 +1,276 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/chroma-core/chroma/go/pkg/common"
+	"github.com/chroma-core/chroma/go/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func collectionNames(collections []*Collection) []string {
+	names := make([]string, 0, len(collections))
+	for _, collection := range collections {
+		names = append(names, collection.Name)
+	}
+	return names
+}
+
+func mustFilterCollections(t *testing.T, collections []*Collection, opts CollectionListOptions) []*Collection {
+	t.Helper()
+	result, err := FilterCollections(collections, opts)
+	assert.NoError(t, err)
+	return result
+}
+
+func mustCountMatching(t *testing.T, collections []*Collection, opts CollectionListOptions) int {
+	t.Helper()
+	count, err := CountMatching(collections, opts)
+	assert.NoError(t, err)
+	return count
+}
+
+func TestFilterCollectionsPagination(t *testing.T) {
+	tenant := "tenant"
+	otherTenant := "other_tenant"
+	database := "database"
+	collections := []*Collection{
+		{ID: types.NewUniqueID(), Name: "d", TenantID: tenant, DatabaseName: database},
+		{ID: types.NewUniqueID(), Name: "b", TenantID: tenant, DatabaseName: database},
+		{ID: types.NewUniqueID(), Name: "e", TenantID: otherTenant, DatabaseName: database},
+		{ID: types.NewUniqueID(), Name: "a", TenantID: tenant, DatabaseName: database},
+		{ID: types.NewUniqueID(), Name: "c", TenantID: tenant, DatabaseName: database},
+	}
+
+	// Test case 1: no options returns everything ordered by name
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, collectionNames(mustFilterCollections(t, collections, CollectionListOptions{})))
+
+	// Test case 2: limit
+	assert.Equal(t, []string{"a", "b"}, collectionNames(mustFilterCollections(t, collections, CollectionListOptions{Limit: 2})))
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, collectionNames(mustFilterCollections(t, collections, CollectionListOptions{Limit: 10})))
+
+	// Test case 3: offset
+	assert.Equal(t, []string{"d", "e"}, collectionNames(mustFilterCollections(t, collections, CollectionListOptions{Offset: 3})))
+
+	// Test case 4: offset and limit combined with a filter
+	opts := CollectionListOptions{TenantID: &tenant, Offset: 1, Limit: 2}
+	assert.Equal(t, []string{"b", "c"}, collectionNames(mustFilterCollections(t, collections, opts)))
+
+	// Test case 5: offset past the end returns an empty slice
+	result := mustFilterCollections(t, collections, CollectionListOptions{Offset: 5})
+	assert.NotNil(t, result)
+	assert.Empty(t, result)
+	assert.Empty(t, mustFilterCollections(t, collections, CollectionListOptions{Offset: 100, Limit: 1}))
+
+	// Test case 6: ID, name and database filters
+	name := "c"
+	assert.Equal(t, []string{"c"}, collectionNames(mustFilterCollections(t, collections, CollectionListOptions{Name: &name})))
+	assert.Equal(t, []string{"d"}, collectionNames(mustFilterCollections(t, collections, CollectionListOptions{ID: collections[0].ID})))
+	otherDatabase := "other_database"
+	assert.Empty(t, mustFilterCollections(t, collections, CollectionListOptions{DatabaseName: &otherDatabase}))
+
+	// Test case 7: the input slice is not reordered
+	assert.Equal(t, "d", collections[0].Name)
+}
+
+func TestFilterCollectionsSort(t *testing.T) {
+	id1 := types.MustParse("00000000-0000-0000-0000-000000000001")
+	id2 := types.MustParse("00000000-0000-0000-0000-000000000002")
+	id3 := types.MustParse("00000000-0000-0000-0000-000000000003")
+	id4 := types.MustParse("00000000-0000-0000-0000-000000000004")
+	collections := []*Collection{
+		{ID: id3, Name: "a", Ts: 30},
+		{ID: id1, Name: "c", Ts: 10},
+		{ID: id4, Name: "b", Ts: 20},
+		{ID: id2, Name: "b", Ts: 40},
+	}
+	ids := func(collections []*Collection) []types.UniqueID {
+		result := make([]types.UniqueID, 0, len(collections))
+		for _, collection := range collections {
+			result = append(result, collection.ID)
+		}
+		return result
+	}
+
+	// Test case 1: by name, ties broken by ID
+	assert.Equal(t, []types.UniqueID{id3, id2, id4, id1}, ids(mustFilterCollections(t, collections, CollectionListOptions{SortBy: SortByName})))
+	assert.Equal(t, []types.UniqueID{id1, id2, id4, id3}, ids(mustFilterCollections(t, collections, CollectionListOptions{SortBy: SortByName, Descending: true})))
+
+	// Test case 2: by created timestamp
+	assert.Equal(t, []types.UniqueID{id1, id4, id3, id2}, ids(mustFilterCollections(t, collections, CollectionListOptions{SortBy: SortByCreatedTs})))
+	assert.Equal(t, []types.UniqueID{id2, id3, id4, id1}, ids(mustFilterCollections(t, collections, CollectionListOptions{SortBy: SortByCreatedTs, Descending: true})))
+
+	// Test case 3: by ID
+	assert.Equal(t, []types.UniqueID{id1, id2, id3, id4}, ids(mustFilterCollections(t, collections, CollectionListOptions{SortBy: SortByID})))
+	assert.Equal(t, []types.UniqueID{id4, id3, id2, id1}, ids(mustFilterCollections(t, collections, CollectionListOptions{SortBy: SortByID, Descending: true})))
+
+	// Test case 4: pagination is applied after sorting
+	assert.Equal(t, []types.UniqueID{id4, id3}, ids(mustFilterCollections(t, collections, CollectionListOptions{SortBy: SortByCreatedTs, Offset: 1, Limit: 2})))
+	assert.Equal(t, []types.UniqueID{id3, id2}, ids(mustFilterCollections(t, collections, CollectionListOptions{SortBy: SortByID, Descending: true, Offset: 1, Limit: 2})))
+}
+
+func TestFilterCollectionByIDs(t *testing.T) {
+	first := &Collection{ID: types.NewUniqueID(), Name: "first"}
+	second := &Collection{ID: types.NewUniqueID(), Name: "second"}
+	third := &Collection{ID: types.NewUniqueID(), Name: "third"}
+
+	// Test case 1: hit and miss
+	assert.True(t, FilterCollectionByIDs(first, []types.UniqueID{second.ID, first.ID}))
+	assert.False(t, FilterCollectionByIDs(third, []types.UniqueID{second.ID, first.ID}))
+
+	// Test case 2: empty set matches all
+	assert.True(t, FilterCollectionByIDs(first, nil))
+	assert.True(t, FilterCollectionByIDs(first, []types.UniqueID{}))
+
+	// Test case 3: duplicate ids
+	assert.True(t, FilterCollectionByIDs(first, []types.UniqueID{first.ID, first.ID}))
+
+	// Test case 4: listing with a set of ids
+	collections := []*Collection{first, second, third}
+	result := mustFilterCollections(t, collections, CollectionListOptions{IDs: []types.UniqueID{third.ID, first.ID, first.ID}})
+	assert.Equal(t, []string{"first", "third"}, collectionNames(result))
+	result = mustFilterCollections(t, collections, CollectionListOptions{IDs: []types.UniqueID{types.NewUniqueID()}})
+	assert.Empty(t, result)
+	assert.Len(t, mustFilterCollections(t, collections, CollectionListOptions{}), 3)
+
+	// Test case 5: the set form matches the slice form
+	set := types.NewUniqueIDSet(second.ID, first.ID)
+	assert.True(t, FilterCollectionByIDSet(first, set))
+	assert.False(t, FilterCollectionByIDSet(third, set))
+	assert.True(t, FilterCollectionByIDSet(third, nil))
+	assert.True(t, FilterCollectionByIDSet(third, &types.UniqueIDSet{}))
+	assert.False(t, FilterCollectionByIDSet(nil, set))
+}
+
+func TestFilterCollectionsCreatedWindow(t *testing.T) {
+	collections := []*Collection{
+		{ID: types.NewUniqueID(), Name: "a", Ts: 100},
+		{ID: types.NewUniqueID(), Name: "b", Ts: 200},
+		{ID: types.NewUniqueID(), Name: "c", Ts: 300},
+	}
+	after := types.Timestamp(200)
+	before := types.Timestamp(300)
+
+	// Test case 1: after only, inclusive
+	opts := CollectionListOptions{CreatedAfter: &after}
+	assert.NoError(t, opts.Validate())
+	assert.Equal(t, []string{"b", "c"}, collectionNames(mustFilterCollections(t, collections, opts)))
+
+	// Test case 2: before only, exclusive
+	opts = CollectionListOptions{CreatedBefore: &before}
+	assert.NoError(t, opts.Validate())
+	assert.Equal(t, []string{"a", "b"}, collectionNames(mustFilterCollections(t, collections, opts)))
+
+	// Test case 3: both bounds
+	opts = CollectionListOptions{CreatedAfter: &after, CreatedBefore: &before}
+	assert.NoError(t, opts.Validate())
+	assert.Equal(t, []string{"b"}, collectionNames(mustFilterCollections(t, collections, opts)))
+	opts = CollectionListOptions{CreatedAfter: &after, CreatedBefore: &after}
+	assert.NoError(t, opts.Validate())
+	assert.Empty(t, mustFilterCollections(t, collections, opts))
+
+	// Test case 4: an inverted window is rejected
+	opts = CollectionListOptions{CreatedAfter: &before, CreatedBefore: &after}
+	err := opts.Validate()
+	assert.ErrorIs(t, err, common.ErrInvalidTimeWindow)
+	var windowErr *InvalidTimeWindowError
+	assert.ErrorAs(t, err, &windowErr)
+	assert.Equal(t, before, windowErr.After)
+	assert.Equal(t, after, windowErr.Before)
+}
+
+func TestExistsByName(t *testing.T) {
+	deletedAt := types.Timestamp(1)
+	collections := []*Collection{
+		nil,
+		{ID: types.NewUniqueID(), Name: "docs", TenantID: "tenant", DatabaseName: "database"},
+		{ID: types.NewUniqueID(), Name: "gone", TenantID: "tenant", DatabaseName: "database", DeletedAt: &deletedAt},
+	}
+
+	// Test case 1: hit in the same scope
+	assert.True(t, ExistsByName(collections, "docs", "tenant", "database"))
+
+	// Test case 2: miss in another tenant or database, or for a deleted collection
+	assert.False(t, ExistsByName(collections, "docs", "other", "database"))
+	assert.False(t, ExistsByName(collections, "docs", "tenant", "other"))
+	assert.False(t, ExistsByName(collections, "gone", "tenant", "database"))
+	assert.False(t, ExistsByName(collections, "missing", "tenant", "database"))
+	assert.False(t, ExistsByName(nil, "docs", "tenant", "database"))
+}
+
+func TestCountMatching(t *testing.T) {
+	deletedAt := types.Timestamp(1)
+	tenant := "tenant"
+	id := types.NewUniqueID()
+	collections := []*Collection{
+		{ID: id, Name: "a", TenantID: "tenant", DatabaseName: "database", Ts: 100},
+		nil,
+		{ID: types.NewUniqueID(), Name: "b", TenantID: "tenant", DatabaseName: "database", Ts: 200, State: CollectionStateCreating},
+		{ID: types.NewUniqueID(), Name: "c", TenantID: "other", DatabaseName: "database", Ts: 300},
+		{ID: types.NewUniqueID(), Name: "d", TenantID: "tenant", DatabaseName: "database", DeletedAt: &deletedAt},
+	}
+
+	tests := []struct {
+		name string
+		opts CollectionListOptions
+	}{
+		{name: "all", opts: CollectionListOptions{}},
+		{name: "tenant", opts: CollectionListOptions{TenantID: &tenant}},
+		{name: "include deleted", opts: CollectionListOptions{IncludeDeleted: true}},
+		{name: "ready only", opts: CollectionListOptions{ReadyOnly: true}},
+		{name: "ids", opts: CollectionListOptions{IDs: []types.UniqueID{id}}},
+		{name: "paged", opts: CollectionListOptions{Limit: 1, Offset: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unpaged := tt.opts
+			unpaged.Limit, unpaged.Offset = 0, 0
+			assert.Equal(t, len(mustFilterCollections(t, collections, unpaged)), mustCountMatching(t, collections, tt.opts))
+		})
+	}
+	assert.Equal(t, 3, mustCountMatching(t, collections, CollectionListOptions{}))
+	assert.Equal(t, 2, mustCountMatching(t, collections, CollectionListOptions{TenantID: &tenant}))
+	assert.Equal(t, 0, mustCountMatching(t, nil, CollectionListOptions{}))
+}
+
+func TestSortByCompactionStaleness(t *testing.T) {
+	id1 := types.MustParse("00000000-0000-0000-0000-000000000001")
+	id2 := types.MustParse("00000000-0000-0000-0000-000000000002")
+
+	// Test case 1: never compacted collections come first
+	collections := []*Collection{
+		{Name: "recent", LastCompactionTime: 90, Ts: 1},
+		{Name: "never", Ts: 5},
+		{Name: "old", LastCompactionTime: 10, Ts: 1},
+	}
+	SortByCompactionStaleness(collections, 100)
+	assert.Equal(t, []string{"never", "old", "recent"}, collectionNames(collections))
+
+	// Test case 2: ordered by age, with future times sorting freshest
+	collections = []*Collection{
+		{Name: "future", LastCompactionTime: 150},
+		{Name: "fresh", LastCompactionTime: 99},
+		{Name: "stale", LastCompactionTime: 50},
+	}
+	SortByCompactionStaleness(collections, 100)
+	assert.Equal(t, []string{"stale", "fresh", "future"}, collectionNames(collections))
+
+	// Test case 3: times after now still order by LastCompactionTime, not Ts
+	collections = []*Collection{
+		{Name: "later", LastCompactionTime: 200, Ts: 1},
+		{Name: "earlier", LastCompactionTime: 100, Ts: 2},
+	}
+	SortByCompactionStaleness(collections, 50)
+	assert.Equal(t, []string{"earlier", "later"}, collectionNames(collections))
+
+	// Test case 4: ties break by Ts then ID, and nil sorts last
+	collections = []*Collection{
+		nil,
+		{ID: id2, Name: "b", Ts: 20},
+		{ID: id1, Name: "c", Ts: 20},
+		{ID: id2, Name: "a", Ts: 10},
+	}
+	SortByCompactionStaleness(collections, 100)
+	assert.Nil(t, collections[3])
+	assert.Equal(t, []string{"a", "c", "b"}, collectionNames(collections[:3]))
+}
@@ -1,8 +1,73 @@
 package model
 
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	MaxMetadataKeys             = 64
+	MaxMetadataKeyBytes         = 128
+	MaxMetadataStringValueBytes = 4096
+	MaxMetadataListLength       = 32
+	MaxMetadataListElementBytes = 128
+
+	// FloatMetadataEpsilon is the tolerance used when comparing float metadata
+	// values: relative to the magnitude above 1 and absolute below it.
+	FloatMetadataEpsilon = 1e-9
+	// FloatMetadataSignificantDigits is the precision NormalizeFloat rounds to.
+	FloatMetadataSignificantDigits = 15
+
+	// ReservedMetadataKeyPrefix marks metadata keys managed by chroma itself.
+	ReservedMetadataKeyPrefix = "chroma:"
+	// IdempotencyKeyMetadataKey stores the idempotency key of the
+	// CreateCollection that created a collection.
+	IdempotencyKeyMetadataKey = ReservedMetadataKeyPrefix + "idempotency_key"
+)
+
 type CollectionMetadataValueType interface {
 	IsCollectionMetadataValueType()
 	Equals(other CollectionMetadataValueType) bool
+	Kind() MetadataValueKind
+	String() string
+}
+
+// MetadataValueKind identifies the variant of a metadata value.
+type MetadataValueKind int
+
+const (
+	KindUnknown MetadataValueKind = iota
+	KindString
+	KindInt
+	KindFloat
+	KindBool
+	KindList
+	KindNone
+	KindDelete
+)
+
+func (k MetadataValueKind) String() string {
+	switch k {
+	case KindString:
+		return "string"
+	case KindInt:
+		return "int"
+	case KindFloat:
+		return "float"
+	case KindBool:
+		return "bool"
+	case KindList:
+		return "list"
+	case KindNone:
+		return "none"
+	case KindDelete:
+		return "delete"
+	default:
+		return "unknown"
+	}
 }
 
 type CollectionMetadataValueStringType struct {
@@ -37,13 +102,50 @@ type CollectionMetadataValueFloat64Type struct {
 
 func (s *CollectionMetadataValueFloat64Type) IsCollectionMetadataValueType() {}
 
+// Equals compares the values to within FloatMetadataEpsilon so that values
+// such as 0.1+0.2 and 0.3 match after a round trip through another
+// representation. Both values are snapped to the same epsilon grid, which
+// keeps the comparison transitive and in agreement with Checksum; two values
+// closer than epsilon may still differ when they straddle a grid step.
 func (s *CollectionMetadataValueFloat64Type) Equals(other CollectionMetadataValueType) bool {
 	if o, ok := other.(*CollectionMetadataValueFloat64Type); ok {
-		return s.Value == o.Value
+		return quantizeFloat(s.Value) == quantizeFloat(o.Value)
 	}
 	return false
 }
 
+// quantizeFloat snaps v to a grid of FloatMetadataEpsilon steps below 1 and of
+// FloatMetadataEpsilon relative steps per binary exponent above it. NaN and
+// infinities are returned unchanged, as are values that would round to one.
+func quantizeFloat(v float64) float64 {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return v
+	}
+	if math.Abs(v) <= 1 {
+		return math.Round(v/FloatMetadataEpsilon) * FloatMetadataEpsilon
+	}
+	frac, exp := math.Frexp(v)
+	quantized := math.Ldexp(math.Round(frac/FloatMetadataEpsilon)*FloatMetadataEpsilon, exp)
+	if math.IsInf(quantized, 0) {
+		return v
+	}
+	return quantized
+}
+
+// NormalizeFloat rounds v to FloatMetadataSignificantDigits significant
+// digits, dropping the representation noise left by float arithmetic. NaN and
+// infinities are returned unchanged.
+func NormalizeFloat(v float64) float64 {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return v
+	}
+	normalized, err := strconv.ParseFloat(strconv.FormatFloat(v, 'g', FloatMetadataSignificantDigits, 64), 64)
+	if err != nil {
+		return v
+	}
+	return normalized
+}
+
 type CollectionMetadataValueBoolType struct {
 	Value bool
 }
@@ -57,6 +159,112 @@ func (s *CollectionMetadataValueBoolType) Equals(other CollectionMetadataValueTy
 	return false
 }
 
+type CollectionMetadataValueStringListType struct {
+	Value []string
+}
+
+func (s *CollectionMetadataValueStringListType) IsCollectionMetadataValueType() {}
+
+// Equals compares lists as sets, ignoring order and repeated elements.
+func (s *CollectionMetadataValueStringListType) Equals(other CollectionMetadataValueType) bool {
+	o, ok := other.(*CollectionMetadataValueStringListType)
+	if !ok {
+		return false
+	}
+	mine := make(map[string]struct{}, len(s.Value))
+	for _, v := range s.Value {
+		mine[v] = struct{}{}
+	}
+	theirs := make(map[string]struct{}, len(o.Value))
+	for _, v := range o.Value {
+		if _, ok := mine[v]; !ok {
+			return false
+		}
+		theirs[v] = struct{}{}
+	}
+	return len(mine) == len(theirs)
+}
+
+// CollectionMetadataValueNoneType is a key explicitly set to null. Unlike
+// CollectionMetadataValueDeleteType it is stored: the key stays present with
+// a null value, which is distinct from the key being absent.
+type CollectionMetadataValueNoneType struct{}
+
+func (s *CollectionMetadataValueNoneType) IsCollectionMetadataValueType() {}
+
+func (s *CollectionMetadataValueNoneType) Equals(other CollectionMetadataValueType) bool {
+	_, ok := other.(*CollectionMetadataValueNoneType)
+	return ok
+}
+
+// CollectionMetadataValueDeleteType is a sentinel used in metadata updates to
+// remove a key from the existing metadata. It is never stored.
+type CollectionMetadataValueDeleteType struct{}
+
+func (s *CollectionMetadataValueDeleteType) IsCollectionMetadataValueType() {}
+
+func (s *CollectionMetadataValueDeleteType) Equals(other CollectionMetadataValueType) bool {
+	_, ok := other.(*CollectionMetadataValueDeleteType)
+	return ok
+}
+
+// Kind and String are defined on pointer receivers and are safe to call on a
+// nil value: the kind is that of the variant and the value renders as nil,
+// e.g. int(nil).
+
+func (s *CollectionMetadataValueStringType) Kind() MetadataValueKind { return KindString }
+
+func (s *CollectionMetadataValueStringType) String() string {
+	if s == nil {
+		return "string(nil)"
+	}
+	return fmt.Sprintf("string(%q)", s.Value)
+}
+
+func (s *CollectionMetadataValueInt64Type) Kind() MetadataValueKind { return KindInt }
+
+func (s *CollectionMetadataValueInt64Type) String() string {
+	if s == nil {
+		return "int(nil)"
+	}
+	return fmt.Sprintf("int(%d)", s.Value)
+}
+
+func (s *CollectionMetadataValueFloat64Type) Kind() MetadataValueKind { return KindFloat }
+
+func (s *CollectionMetadataValueFloat64Type) String() string {
+	if s == nil {
+		return "float(nil)"
+	}
+	return fmt.Sprintf("float(%s)", strconv.FormatFloat(s.Value, 'g', -1, 64))
+}
+
+func (s *CollectionMetadataValueBoolType) Kind() MetadataValueKind { return KindBool }
+
+func (s *CollectionMetadataValueBoolType) String() string {
+	if s == nil {
+		return "bool(nil)"
+	}
+	return fmt.Sprintf("bool(%t)", s.Value)
+}
+
+func (s *CollectionMetadataValueStringListType) Kind() MetadataValueKind { return KindList }
+
+func (s *CollectionMetadataValueStringListType) String() string {
+	if s == nil {
+		return "list(nil)"
+	}
+	return fmt.Sprintf("list(%q)", s.Value)
+}
+
+func (s *CollectionMetadataValueNoneType) Kind() MetadataValueKind { return KindNone }
+
+func (s *CollectionMetadataValueNoneType) String() string { return "none" }
+
+func (s *CollectionMetadataValueDeleteType) Kind() MetadataValueKind { return KindDelete }
+
+func (s *CollectionMetadataValueDeleteType) String() string { return "delete" }
+
 type CollectionMetadata[T CollectionMetadataValueType] struct {
 	Metadata map[string]T
 }
@@ -75,6 +283,101 @@ func (m *CollectionMetadata[T]) Get(key string) T {
 	return m.Metadata[key]
 }
 
+func (m *CollectionMetadata[T]) lookup(key string) (any, bool) {
+	if m == nil {
+		return nil, false
+	}
+	value, ok := m.Metadata[key]
+	return value, ok
+}
+
+func (m *CollectionMetadata[T]) GetString(key string) (string, bool) {
+	value, _ := m.lookup(key)
+	if v, ok := value.(*CollectionMetadataValueStringType); ok && v != nil {
+		return v.Value, true
+	}
+	return "", false
+}
+
+func (m *CollectionMetadata[T]) GetInt(key string) (int64, bool) {
+	value, _ := m.lookup(key)
+	if v, ok := value.(*CollectionMetadataValueInt64Type); ok && v != nil {
+		return v.Value, true
+	}
+	return 0, false
+}
+
+func (m *CollectionMetadata[T]) GetFloat(key string) (float64, bool) {
+	value, _ := m.lookup(key)
+	if v, ok := value.(*CollectionMetadataValueFloat64Type); ok && v != nil {
+		return v.Value, true
+	}
+	return 0, false
+}
+
+func (m *CollectionMetadata[T]) GetBool(key string) (bool, bool) {
+	value, _ := m.lookup(key)
+	if v, ok := value.(*CollectionMetadataValueBoolType); ok && v != nil {
+		return v.Value, true
+	}
+	return false, false
+}
+
+// lookupFold finds key ignoring case. When several keys fold to the same name
+// the lexicographically first one is used.
+func (m *CollectionMetadata[T]) lookupFold(key string) (any, bool) {
+	if m == nil {
+		return nil, false
+	}
+	var match string
+	found := false
+	for k := range m.Metadata {
+		if strings.EqualFold(k, key) && (!found || k < match) {
+			match, found = k, true
+		}
+	}
+	if !found {
+		return nil, false
+	}
+	return m.Metadata[match], true
+}
+
+// GetStringFold is GetString with case-insensitive key matching.
+func (m *CollectionMetadata[T]) GetStringFold(key string) (string, bool) {
+	value, _ := m.lookupFold(key)
+	if v, ok := value.(*CollectionMetadataValueStringType); ok && v != nil {
+		return v.Value, true
+	}
+	return "", false
+}
+
+// GetIntFold is GetInt with case-insensitive key matching.
+func (m *CollectionMetadata[T]) GetIntFold(key string) (int64, bool) {
+	value, _ := m.lookupFold(key)
+	if v, ok := value.(*CollectionMetadataValueInt64Type); ok && v != nil {
+		return v.Value, true
+	}
+	return 0, false
+}
+
+// GetFloatFold is GetFloat with case-insensitive key matching.
+func (m *CollectionMetadata[T]) GetFloatFold(key string) (float64, bool) {
+	value, _ := m.lookupFold(key)
+	if v, ok := value.(*CollectionMetadataValueFloat64Type); ok && v != nil {
+		return v.Value, true
+	}
+	return 0, false
+}
+
+// GetBoolFold is GetBool with case-insensitive key matching.
+func (m *CollectionMetadata[T]) GetBoolFold(key string) (bool, bool) {
+	value, _ := m.lookupFold(key)
+	if v, ok := value.(*CollectionMetadataValueBoolType); ok && v != nil {
+		return v.Value, true
+	}
+	return false, false
+}
+
 func (m *CollectionMetadata[T]) Remove(key string) {
 	delete(m.Metadata, key)
 }
@@ -83,6 +386,20 @@ func (m *CollectionMetadata[T]) Empty() bool {
 	return len(m.Metadata) == 0
 }
 
+// SortedKeys returns the metadata keys in ascending order. Serializers iterate
+// in this order so that equal metadata always produces identical output.
+func (m *CollectionMetadata[T]) SortedKeys() []string {
+	if m == nil {
+		return nil
+	}
+	keys := make([]string, 0, len(m.Metadata))
+	for key := range m.Metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func (m *CollectionMetadata[T]) Equals(other *CollectionMetadata[T]) bool {
 	if m == nil && other == nil {
 		return true
@@ -103,3 +420,169 @@ func (m *CollectionMetadata[T]) Equals(other *CollectionMetadata[T]) bool {
 	}
 	return true
 }
+
+func cloneCollectionMetadataValue(value CollectionMetadataValueType) CollectionMetadataValueType {
+	switch v := value.(type) {
+	case *CollectionMetadataValueStringType:
+		return &CollectionMetadataValueStringType{Value: v.Value}
+	case *CollectionMetadataValueInt64Type:
+		return &CollectionMetadataValueInt64Type{Value: v.Value}
+	case *CollectionMetadataValueFloat64Type:
+		return &CollectionMetadataValueFloat64Type{Value: v.Value}
+	case *CollectionMetadataValueBoolType:
+		return &CollectionMetadataValueBoolType{Value: v.Value}
+	case *CollectionMetadataValueStringListType:
+		return &CollectionMetadataValueStringListType{Value: append([]string(nil), v.Value...)}
+	case *CollectionMetadataValueNoneType:
+		return &CollectionMetadataValueNoneType{}
+	case *CollectionMetadataValueDeleteType:
+		return &CollectionMetadataValueDeleteType{}
+	default:
+		return value
+	}
+}
+
+// Clone returns a copy of the metadata whose map and values are not shared
+// with m.
+func (m *CollectionMetadata[T]) Clone() *CollectionMetadata[T] {
+	if m == nil {
+		return nil
+	}
+	clone := &CollectionMetadata[T]{
+		Metadata: make(map[string]T, len(m.Metadata)),
+	}
+	for key, value := range m.Metadata {
+		cloned, _ := cloneCollectionMetadataValue(value).(T)
+		clone.Metadata[key] = cloned
+	}
+	return clone
+}
+
+// CollectionMetadataFromMap converts decoded values such as those produced by
+// encoding/json into typed metadata. Strings, ints, floats, bools, lists of
+// strings and nil (as CollectionMetadataValueNoneType) are supported. Floats
+// are stored normalized with NormalizeFloat. A nil or empty map yields nil
+// metadata.
+func CollectionMetadataFromMap(m map[string]interface{}) (*CollectionMetadata[CollectionMetadataValueType], error) {
+	if len(m) == 0 {
+		return nil, nil
+	}
+	metadata := NewCollectionMetadata[CollectionMetadataValueType]()
+	for key, value := range m {
+		converted, err := collectionMetadataValueFromInterface(key, value)
+		if err != nil {
+			return nil, err
+		}
+		metadata.Add(key, converted)
+	}
+	return metadata, nil
+}
+
+func collectionMetadataValueFromInterface(key string, value interface{}) (CollectionMetadataValueType, error) {
+	switch v := value.(type) {
+	case nil:
+		return &CollectionMetadataValueNoneType{}, nil
+	case string:
+		return &CollectionMetadataValueStringType{Value: v}, nil
+	case int:
+		return &CollectionMetadataValueInt64Type{Value: int64(v)}, nil
+	case int32:
+		return &CollectionMetadataValueInt64Type{Value: int64(v)}, nil
+	case int64:
+		return &CollectionMetadataValueInt64Type{Value: v}, nil
+	case float64:
+		return &CollectionMetadataValueFloat64Type{Value: NormalizeFloat(v)}, nil
+	case bool:
+		return &CollectionMetadataValueBoolType{Value: v}, nil
+	case []string:
+		return &CollectionMetadataValueStringListType{Value: append([]string(nil), v...)}, nil
+	case []interface{}:
+		list := make([]string, 0, len(v))
+		for _, element := range v {
+			str, ok := element.(string)
+			if !ok {
+				return nil, &UnsupportedMetadataValueError{Key: key, Value: value}
+			}
+			list = append(list, str)
+		}
+		return &CollectionMetadataValueStringListType{Value: list}, nil
+	default:
+		return nil, &UnsupportedMetadataValueError{Key: key, Value: value}
+	}
+}
+
+func IsReservedKey(key string) bool {
+	return strings.HasPrefix(key, ReservedMetadataKeyPrefix)
+}
+
+// SetReservedMetadata stores a chroma-managed value, bypassing the reserved
+// key check applied to user metadata. key is prefixed with
+// ReservedMetadataKeyPrefix when it does not already carry it. A nil m is
+// allocated; the resulting metadata is returned.
+func SetReservedMetadata(m *CollectionMetadata[CollectionMetadataValueType], key string, value CollectionMetadataValueType) *CollectionMetadata[CollectionMetadataValueType] {
+	if m == nil {
+		m = NewCollectionMetadata[CollectionMetadataValueType]()
+	}
+	if !IsReservedKey(key) {
+		key = ReservedMetadataKeyPrefix + key
+	}
+	m.Add(key, value)
+	return m
+}
+
+// validateUserMetadata rejects reserved keys in metadata supplied by users.
+func validateUserMetadata(m *CollectionMetadata[CollectionMetadataValueType]) error {
+	if m == nil {
+		return nil
+	}
+	keys := make([]string, 0, len(m.Metadata))
+	for key := range m.Metadata {
+		if IsReservedKey(key) {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	sort.Strings(keys)
+	return &ReservedMetadataKeyError{Key: keys[0]}
+}
+
+// ValidateMetadata enforces the metadata size limits. Nil metadata is valid.
+// Reserved keys are managed by chroma and do not count toward
+// MaxMetadataKeys, so stamping one never pushes a collection over the limit.
+func ValidateMetadata(m *CollectionMetadata[CollectionMetadataValueType]) error {
+	if m == nil {
+		return nil
+	}
+	userKeys := 0
+	for key := range m.Metadata {
+		if !IsReservedKey(key) {
+			userKeys++
+		}
+	}
+	if userKeys > MaxMetadataKeys {
+		return &MetadataTooLargeError{Limit: MaxMetadataKeys, Size: userKeys}
+	}
+	for _, key := range m.SortedKeys() {
+		if len(key) > MaxMetadataKeyBytes {
+			return &MetadataTooLargeError{Key: key, Limit: MaxMetadataKeyBytes, Size: len(key)}
+		}
+		switch v := m.Metadata[key].(type) {
+		case *CollectionMetadataValueStringType:
+			if len(v.Value) > MaxMetadataStringValueBytes {
+				return &MetadataTooLargeError{Key: key, Limit: MaxMetadataStringValueBytes, Size: len(v.Value)}
+			}
+		case *CollectionMetadataValueStringListType:
+			if len(v.Value) > MaxMetadataListLength {
+				return &MetadataTooLargeError{Key: key, Limit: MaxMetadataListLength, Size: len(v.Value)}
+			}
+			for _, element := range v.Value {
+				if len(element) > MaxMetadataListElementBytes {
+					return &MetadataTooLargeError{Key: key, Limit: MaxMetadataListElementBytes, Size: len(element)}
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package model
+
+import (
+	"encoding/json"
+
+	"github.com/chroma-core/chroma/go/pkg/types"
+)
+
+// MetadataRow is one metadata key stored as a typed key-value row. ValueType
+// is one of the metadata JSON type names and selects the populated column;
+// the other value columns are nil. String lists are stored as a JSON array in
+// StrValue and none values populate no column.
+type MetadataRow struct {
+	CollectionID types.UniqueID
+	Key          string
+	ValueType    string
+	StrValue     *string
+	IntValue     *int64
+	FloatValue   *float64
+	BoolValue    *bool
+}
+
+// ToKeyValueRows exports the metadata as one row per key, ordered by key.
+// Delete sentinels and unknown value types are not stored and produce no row.
+// Nil or empty metadata produces no rows.
+func (m *CollectionMetadata[T]) ToKeyValueRows(collectionID types.UniqueID) []MetadataRow {
+	if m == nil || len(m.Metadata) == 0 {
+		return nil
+	}
+	keys := m.SortedKeys()
+	rows := make([]MetadataRow, 0, len(keys))
+	for _, key := range keys {
+		row := MetadataRow{CollectionID: collectionID, Key: key}
+		switch v := CollectionMetadataValueType(m.Metadata[key]).(type) {
+		case *CollectionMetadataValueStringType:
+			value := v.Value
+			row.ValueType, row.StrValue = metadataValueJSONTypeString, &value
+		case *CollectionMetadataValueInt64Type:
+			value := v.Value
+			row.ValueType, row.IntValue = metadataValueJSONTypeInt, &value
+		case *CollectionMetadataValueFloat64Type:
+			value := v.Value
+			row.ValueType, row.FloatValue = metadataValueJSONTypeFloat, &value
+		case *CollectionMetadataValueBoolType:
+			value := v.Value
+			row.ValueType, row.BoolValue = metadataValueJSONTypeBool, &value
+		case *CollectionMetadataValueStringListType:
+			list := v.Value
+			if list == nil {
+				list = []string{}
+			}
+			encoded, err := json.Marshal(list)
+			if err != nil {
+				continue
+			}
+			value := string(encoded)
+			row.ValueType, row.StrValue = metadataValueJSONTypeList, &value
+		case *CollectionMetadataValueNoneType:
+			row.ValueType = metadataValueJSONTypeNone
+		default:
+			continue
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// MetadataFromKeyValueRows is the inverse of ToKeyValueRows. Rows with an
+// unknown ValueType or a missing value column are skipped. No usable rows
+// yields nil metadata.
+func MetadataFromKeyValueRows(rows []MetadataRow) *CollectionMetadata[CollectionMetadataValueType] {
+	var metadata *CollectionMetadata[CollectionMetadataValueType]
+	for _, row := range rows {
+		value := metadataValueFromRow(row)
+		if value == nil {
+			continue
+		}
+		if metadata == nil {
+			metadata = NewCollectionMetadata[CollectionMetadataValueType]()
+		}
+		metadata.Add(row.Key, value)
+	}
+	return metadata
+}
+
+func metadataValueFromRow(row MetadataRow) CollectionMetadataValueType {
+	switch row.ValueType {
+	case metadataValueJSONTypeString:
+		if row.StrValue != nil {
+			return &CollectionMetadataValueStringType{Value: *row.StrValue}
+		}
+	case metadataValueJSONTypeInt:
+		if row.IntValue != nil {
+			return &CollectionMetadataValueInt64Type{Value: *row.IntValue}
+		}
+	case metadataValueJSONTypeFloat:
+		if row.FloatValue != nil {
+			return &CollectionMetadataValueFloat64Type{Value: *row.FloatValue}
+		}
+	case metadataValueJSONTypeBool:
+		if row.BoolValue != nil {
+			return &CollectionMetadataValueBoolType{Value: *row.BoolValue}
+		}
+	case metadataValueJSONTypeList:
+		if row.StrValue != nil {
+			var list []string
+			if err := json.Unmarshal([]byte(*row.StrValue), &list); err == nil {
+				return &CollectionMetadataValueStringListType{Value: list}
+			}
+		}
+	case metadataValueJSONTypeNone:
+		return &CollectionMetadataValueNoneType{}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/chroma-core/chroma/go/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetadataKeyValueRows(t *testing.T) {
+	collectionID := types.NewUniqueID()
+	metadata := NewCollectionMetadata[CollectionMetadataValueType]()
+	metadata.Add("string", &CollectionMetadataValueStringType{Value: "1"})
+	metadata.Add("int", &CollectionMetadataValueInt64Type{Value: 9007199254740993})
+	metadata.Add("float", &CollectionMetadataValueFloat64Type{Value: 1})
+	metadata.Add("bool", &CollectionMetadataValueBoolType{Value: false})
+	metadata.Add("list", &CollectionMetadataValueStringListType{Value: []string{"b", "a"}})
+	metadata.Add("empty_list", &CollectionMetadataValueStringListType{Value: []string{}})
+	metadata.Add("none", &CollectionMetadataValueNoneType{})
+
+	// Test case 1: one row per key, ordered by key, with one populated column
+	rows := metadata.ToKeyValueRows(collectionID)
+	keys := make([]string, 0, len(rows))
+	for _, row := range rows {
+		keys = append(keys, row.Key)
+		assert.Equal(t, collectionID, row.CollectionID)
+	}
+	assert.Equal(t, []string{"bool", "empty_list", "float", "int", "list", "none", "string"}, keys)
+	assert.Equal(t, MetadataRow{CollectionID: collectionID, Key: "bool", ValueType: "bool", BoolValue: boolPtr(false)}, rows[0])
+	assert.Equal(t, MetadataRow{CollectionID: collectionID, Key: "float", ValueType: "float", FloatValue: float64Ptr(1)}, rows[2])
+	assert.Equal(t, MetadataRow{CollectionID: collectionID, Key: "int", ValueType: "int", IntValue: int64Ptr(9007199254740993)}, rows[3])
+	assert.Equal(t, MetadataRow{CollectionID: collectionID, Key: "list", ValueType: "string_list", StrValue: stringPtr(`["b","a"]`)}, rows[4])
+	assert.Equal(t, MetadataRow{CollectionID: collectionID, Key: "none", ValueType: "none"}, rows[5])
+	assert.Equal(t, MetadataRow{CollectionID: collectionID, Key: "string", ValueType: "string", StrValue: stringPtr("1")}, rows[6])
+
+	// Test case 2: the round trip preserves every value type exactly
+	decoded := MetadataFromKeyValueRows(rows)
+	assert.Equal(t, metadata, decoded)
+
+	// Test case 3: empty metadata produces zero rows
+	assert.Empty(t, NewCollectionMetadata[CollectionMetadataValueType]().ToKeyValueRows(collectionID))
+	var nilMetadata *CollectionMetadata[CollectionMetadataValueType]
+	assert.Empty(t, nilMetadata.ToKeyValueRows(collectionID))
+	assert.Nil(t, MetadataFromKeyValueRows(nil))
+
+	// Test case 4: delete sentinels and malformed rows are skipped
+	withDelete := NewCollectionMetadata[CollectionMetadataValueType]()
+	withDelete.Add("removed", &CollectionMetadataValueDeleteType{})
+	assert.Empty(t, withDelete.ToKeyValueRows(collectionID))
+	assert.Nil(t, MetadataFromKeyValueRows([]MetadataRow{
+		{Key: "unknown", ValueType: "unknown", StrValue: stringPtr("value")},
+		{Key: "missing", ValueType: "int"},
+		{Key: "bad_list", ValueType: "string_list", StrValue: stringPtr("not json")},
+	}))
+}
+
+func int64Ptr(v int64) *int64 {
+	return &v
+}
+
+func float64Ptr(v float64) *float64 {
+	return &v
+}
+
+func boolPtr(v bool) *bool {
+	return &v
+}
@@ -0,0 +1,584 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/chroma-core/chroma/go/pkg/common"
+	"github.com/chroma-core/chroma/go/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectionMetadataTypedGetters(t *testing.T) {
+	metadata := NewCollectionMetadata[CollectionMetadataValueType]()
+	metadata.Add("string", &CollectionMetadataValueStringType{Value: "value"})
+	metadata.Add("int", &CollectionMetadataValueInt64Type{Value: 42})
+	metadata.Add("float", &CollectionMetadataValueFloat64Type{Value: 4.2})
+	metadata.Add("bool", &CollectionMetadataValueBoolType{Value: true})
+
+	// Test case 1: present keys with matching types
+	str, ok := metadata.GetString("string")
+	assert.True(t, ok)
+	assert.Equal(t, "value", str)
+	i, ok := metadata.GetInt("int")
+	assert.True(t, ok)
+	assert.Equal(t, int64(42), i)
+	f, ok := metadata.GetFloat("float")
+	assert.True(t, ok)
+	assert.Equal(t, 4.2, f)
+	b, ok := metadata.GetBool("bool")
+	assert.True(t, ok)
+	assert.True(t, b)
+
+	// Test case 2: absent keys
+	str, ok = metadata.GetString("missing")
+	assert.False(t, ok)
+	assert.Equal(t, "", str)
+	i, ok = metadata.GetInt("missing")
+	assert.False(t, ok)
+	assert.Equal(t, int64(0), i)
+	f, ok = metadata.GetFloat("missing")
+	assert.False(t, ok)
+	assert.Equal(t, float64(0), f)
+	b, ok = metadata.GetBool("missing")
+	assert.False(t, ok)
+	assert.False(t, b)
+
+	// Test case 3: present keys with the wrong type
+	str, ok = metadata.GetString("int")
+	assert.False(t, ok)
+	assert.Equal(t, "", str)
+	i, ok = metadata.GetInt("float")
+	assert.False(t, ok)
+	assert.Equal(t, int64(0), i)
+	f, ok = metadata.GetFloat("int")
+	assert.False(t, ok)
+	assert.Equal(t, float64(0), f)
+	b, ok = metadata.GetBool("string")
+	assert.False(t, ok)
+	assert.False(t, b)
+
+	// Test case 4: nil metadata
+	var nilMetadata *CollectionMetadata[CollectionMetadataValueType]
+	_, ok = nilMetadata.GetString("string")
+	assert.False(t, ok)
+	_, ok = nilMetadata.GetInt("int")
+	assert.False(t, ok)
+	_, ok = nilMetadata.GetFloat("float")
+	assert.False(t, ok)
+	_, ok = nilMetadata.GetBool("bool")
+	assert.False(t, ok)
+}
+
+func TestValidateMetadata(t *testing.T) {
+	// Test case 1: nil and empty metadata are valid
+	assert.NoError(t, ValidateMetadata(nil))
+	assert.NoError(t, ValidateMetadata(NewCollectionMetadata[CollectionMetadataValueType]()))
+
+	// Test case 2: key count at and beyond the limit
+	metadata := NewCollectionMetadata[CollectionMetadataValueType]()
+	for i := 0; i < MaxMetadataKeys; i++ {
+		metadata.Add(fmt.Sprintf("key%d", i), &CollectionMetadataValueInt64Type{Value: int64(i)})
+	}
+	assert.NoError(t, ValidateMetadata(metadata))
+	metadata.Add("one_too_many", &CollectionMetadataValueInt64Type{Value: 0})
+	var tooLarge *MetadataTooLargeError
+	assert.ErrorAs(t, ValidateMetadata(metadata), &tooLarge)
+	assert.Equal(t, MaxMetadataKeys, tooLarge.Limit)
+	assert.Equal(t, MaxMetadataKeys+1, tooLarge.Size)
+
+	// Test case 3: key length at and beyond the limit
+	metadata = NewCollectionMetadata[CollectionMetadataValueType]()
+	metadata.Add(strings.Repeat("k", MaxMetadataKeyBytes), &CollectionMetadataValueBoolType{Value: true})
+	assert.NoError(t, ValidateMetadata(metadata))
+	longKey := strings.Repeat("k", MaxMetadataKeyBytes+1)
+	metadata.Add(longKey, &CollectionMetadataValueBoolType{Value: true})
+	assert.ErrorAs(t, ValidateMetadata(metadata), &tooLarge)
+	assert.Equal(t, longKey, tooLarge.Key)
+	assert.Equal(t, MaxMetadataKeyBytes, tooLarge.Limit)
+
+	// Test case 4: string value length at and beyond the limit
+	metadata = NewCollectionMetadata[CollectionMetadataValueType]()
+	metadata.Add("value", &CollectionMetadataValueStringType{Value: strings.Repeat("v", MaxMetadataStringValueBytes)})
+	assert.NoError(t, ValidateMetadata(metadata))
+	metadata.Add("big", &CollectionMetadataValueStringType{Value: strings.Repeat("v", MaxMetadataStringValueBytes+1)})
+	err := ValidateMetadata(metadata)
+	assert.ErrorAs(t, err, &tooLarge)
+	assert.Equal(t, "big", tooLarge.Key)
+	assert.Equal(t, MaxMetadataStringValueBytes+1, tooLarge.Size)
+	assert.Contains(t, err.Error(), `"big"`)
+
+	// Test case 5: wired into create and update validation
+	create := &CreateCollection{Name: "collection", Metadata: metadata}
+	assert.ErrorAs(t, create.Validate(), &tooLarge)
+	update := &UpdateCollection{TenantID: "tenant", DatabaseName: "database", Metadata: metadata}
+	assert.ErrorAs(t, update.Validate(&Collection{}), &tooLarge)
+}
+
+func TestValidateMetadataOnMergedUpdate(t *testing.T) {
+	existingMetadata := NewCollectionMetadata[CollectionMetadataValueType]()
+	for i := 0; i < MaxMetadataKeys; i++ {
+		existingMetadata.Add(fmt.Sprintf("key%d", i), &CollectionMetadataValueInt64Type{Value: int64(i)})
+	}
+	existing := &Collection{TenantID: "tenant", DatabaseName: "database", Metadata: existingMetadata}
+
+	// Test case 1: merging a new key over a full collection exceeds the key limit
+	updateMetadata := NewCollectionMetadata[CollectionMetadataValueType]()
+	updateMetadata.Add("new", &CollectionMetadataValueInt64Type{Value: 1})
+	var tooLarge *MetadataTooLargeError
+	assert.ErrorAs(t, (&UpdateCollection{TenantID: "tenant", DatabaseName: "database", Metadata: updateMetadata}).Validate(existing), &tooLarge)
+
+	// Test case 2: resetting to the same key is within the limit
+	assert.NoError(t, (&UpdateCollection{TenantID: "tenant", DatabaseName: "database", Metadata: updateMetadata, ResetMetadata: true}).Validate(existing))
+
+	// Test case 3: deleting a key makes room for the new one
+	updateMetadata.Add("key0", &CollectionMetadataValueDeleteType{})
+	assert.NoError(t, (&UpdateCollection{TenantID: "tenant", DatabaseName: "database", Metadata: updateMetadata}).Validate(existing))
+
+	// Test case 4: a stamped idempotency key does not count toward the limit
+	key := "request-1"
+	create := &CreateCollection{ID: types.NewUniqueID(), Name: "collection", TenantID: "tenant", DatabaseName: "database", Metadata: existingMetadata, IdempotencyKey: &key}
+	assert.NoError(t, create.Validate())
+	created := NewCollectionFromCreate(create)
+	assert.Len(t, created.Metadata.Metadata, MaxMetadataKeys+1)
+	changeMetadata := NewCollectionMetadata[CollectionMetadataValueType]()
+	changeMetadata.Add("key1", &CollectionMetadataValueInt64Type{Value: 100})
+	assert.NoError(t, (&UpdateCollection{ID: created.ID, TenantID: "tenant", DatabaseName: "database", Metadata: changeMetadata}).Validate(created))
+}
+
+func TestReservedMetadataKeys(t *testing.T) {
+	// Test case 1: reserved key detection
+	assert.True(t, IsReservedKey("chroma:embedding_function"))
+	assert.True(t, IsReservedKey("chroma:"))
+	assert.False(t, IsReservedKey("chroma_embedding_function"))
+	assert.False(t, IsReservedKey("source"))
+
+	// Test case 2: user supplied reserved keys are rejected on create
+	userMetadata := NewCollectionMetadata[CollectionMetadataValueType]()
+	userMetadata.Add("source", &CollectionMetadataValueStringType{Value: "web"})
+	userMetadata.Add("chroma:embedding_function", &CollectionMetadataValueStringType{Value: "mine"})
+	create := &CreateCollection{Name: "collection", Metadata: userMetadata}
+	err := create.Validate()
+	var reservedErr *ReservedMetadataKeyError
+	assert.ErrorAs(t, err, &reservedErr)
+	assert.Equal(t, "chroma:embedding_function", reservedErr.Key)
+	assert.Contains(t, err.Error(), "reserved prefix")
+
+	// Test case 3: user supplied reserved keys are rejected on update
+	update := &UpdateCollection{TenantID: "tenant", DatabaseName: "database", Metadata: userMetadata}
+	assert.ErrorAs(t, update.Validate(&Collection{TenantID: "tenant", DatabaseName: "database"}), &reservedErr)
+
+	// Test case 4: the internal setter bypasses the check
+	metadata := SetReservedMetadata(nil, "embedding_function", &CollectionMetadataValueStringType{Value: "default"})
+	value, ok := metadata.GetString("chroma:embedding_function")
+	assert.True(t, ok)
+	assert.Equal(t, "default", value)
+	same := SetReservedMetadata(metadata, "chroma:legacy", &CollectionMetadataValueBoolType{Value: true})
+	assert.Same(t, metadata, same)
+	_, ok = metadata.GetBool("chroma:legacy")
+	assert.True(t, ok)
+
+	// Test case 5: existing reserved keys don't block a user update
+	existing := &Collection{TenantID: "tenant", DatabaseName: "database", Metadata: metadata}
+	updateMetadata := NewCollectionMetadata[CollectionMetadataValueType]()
+	updateMetadata.Add("source", &CollectionMetadataValueStringType{Value: "api"})
+	update = &UpdateCollection{TenantID: "tenant", DatabaseName: "database", Metadata: updateMetadata}
+	assert.NoError(t, update.Validate(existing))
+}
+
+func TestCollectionMetadataStringList(t *testing.T) {
+	tags := &CollectionMetadataValueStringListType{Value: []string{"ml", "prod"}}
+
+	// Test case 1: set equality ignores order and repeats
+	assert.True(t, tags.Equals(&CollectionMetadataValueStringListType{Value: []string{"prod", "ml"}}))
+	assert.True(t, tags.Equals(&CollectionMetadataValueStringListType{Value: []string{"prod", "ml", "ml"}}))
+	assert.False(t, tags.Equals(&CollectionMetadataValueStringListType{Value: []string{"ml"}}))
+	assert.False(t, tags.Equals(&CollectionMetadataValueStringListType{Value: []string{"ml", "dev"}}))
+	assert.False(t, tags.Equals(&CollectionMetadataValueStringType{Value: "ml"}))
+	assert.True(t, (&CollectionMetadataValueStringListType{}).Equals(&CollectionMetadataValueStringListType{Value: []string{}}))
+
+	// Test case 2: metadata matching uses set equality
+	metadata := NewCollectionMetadata[CollectionMetadataValueType]()
+	metadata.Add("tags", tags)
+	collection := &Collection{Metadata: metadata}
+	assert.True(t, FilterCollectionByMetadata(collection, map[string]CollectionMetadataValueType{
+		"tags": &CollectionMetadataValueStringListType{Value: []string{"prod", "ml"}},
+	}))
+	assert.False(t, FilterCollectionByMetadata(collection, map[string]CollectionMetadataValueType{
+		"tags": &CollectionMetadataValueStringListType{Value: []string{"prod"}},
+	}))
+
+	// Test case 3: size limits
+	list := make([]string, MaxMetadataListLength)
+	for i := range list {
+		list[i] = strings.Repeat("e", MaxMetadataListElementBytes)
+	}
+	metadata = NewCollectionMetadata[CollectionMetadataValueType]()
+	metadata.Add("tags", &CollectionMetadataValueStringListType{Value: list})
+	assert.NoError(t, ValidateMetadata(metadata))
+	metadata.Add("tags", &CollectionMetadataValueStringListType{Value: append(list, "one_too_many")})
+	var tooLarge *MetadataTooLargeError
+	assert.ErrorAs(t, ValidateMetadata(metadata), &tooLarge)
+	assert.Equal(t, MaxMetadataListLength, tooLarge.Limit)
+	metadata.Add("tags", &CollectionMetadataValueStringListType{Value: []string{strings.Repeat("e", MaxMetadataListElementBytes+1)}})
+	assert.ErrorAs(t, ValidateMetadata(metadata), &tooLarge)
+	assert.Equal(t, MaxMetadataListElementBytes, tooLarge.Limit)
+
+	// Test case 4: clone copies the list
+	original := &Collection{Metadata: NewCollectionMetadata[CollectionMetadataValueType]()}
+	original.Metadata.Add("tags", &CollectionMetadataValueStringListType{Value: []string{"ml", "prod"}})
+	clone := original.Clone()
+	clone.Metadata.Get("tags").(*CollectionMetadataValueStringListType).Value[0] = "changed"
+	assert.Equal(t, []string{"ml", "prod"}, original.Metadata.Get("tags").(*CollectionMetadataValueStringListType).Value)
+}
+
+func TestCollectionMetadataNone(t *testing.T) {
+	none := &CollectionMetadataValueNoneType{}
+	assert.True(t, none.Equals(&CollectionMetadataValueNoneType{}))
+	assert.False(t, none.Equals(&CollectionMetadataValueDeleteType{}))
+	assert.False(t, none.Equals(&CollectionMetadataValueStringType{Value: ""}))
+
+	// Test case 1: a none value survives a JSON round trip as a present key
+	metadata := NewCollectionMetadata[CollectionMetadataValueType]()
+	metadata.Add("nullable", none)
+	data, err := json.Marshal(&Collection{Metadata: metadata})
+	assert.NoError(t, err)
+	decoded := &Collection{}
+	assert.NoError(t, json.Unmarshal(data, decoded))
+	value, ok := decoded.Metadata.Metadata["nullable"]
+	assert.True(t, ok)
+	assert.IsType(t, &CollectionMetadataValueNoneType{}, value)
+
+	// Test case 2: merge stores a none value instead of deleting the key
+	existingMetadata := NewCollectionMetadata[CollectionMetadataValueType]()
+	existingMetadata.Add("nullable", &CollectionMetadataValueStringType{Value: "value"})
+	existingMetadata.Add("removed", &CollectionMetadataValueStringType{Value: "value"})
+	updateMetadata := NewCollectionMetadata[CollectionMetadataValueType]()
+	updateMetadata.Add("nullable", &CollectionMetadataValueNoneType{})
+	updateMetadata.Add("removed", &CollectionMetadataValueDeleteType{})
+	result := ApplyMetadataUpdate(&Collection{Metadata: existingMetadata}, &UpdateCollection{Metadata: updateMetadata})
+	assert.Len(t, result.Metadata, 1)
+	assert.IsType(t, &CollectionMetadataValueNoneType{}, result.Metadata["nullable"])
+
+	// Test case 3: a metadata of only none values is not coalesced to nil
+	onlyNone := NewCollectionMetadata[CollectionMetadataValueType]()
+	onlyNone.Add("nullable", &CollectionMetadataValueNoneType{})
+	result = ApplyMetadataUpdate(nil, &UpdateCollection{Metadata: onlyNone, ResetMetadata: true})
+	assert.NotNil(t, result)
+	assert.True(t, result.Equals(onlyNone))
+}
+
+func TestCollectionMetadataBool(t *testing.T) {
+	active := &CollectionMetadataValueBoolType{Value: true}
+	assert.True(t, active.Equals(&CollectionMetadataValueBoolType{Value: true}))
+	assert.False(t, active.Equals(&CollectionMetadataValueBoolType{Value: false}))
+	assert.False(t, active.Equals(&CollectionMetadataValueStringType{Value: "true"}))
+	assert.False(t, active.Equals(&CollectionMetadataValueInt64Type{Value: 1}))
+	assert.False(t, (&CollectionMetadataValueStringType{Value: "true"}).Equals(active))
+
+	// Test case 1: bools are converted from decoded maps without coercion
+	metadata, err := CollectionMetadataFromMap(map[string]interface{}{"active": true, "label": "true"})
+	assert.NoError(t, err)
+	assert.Equal(t, &CollectionMetadataValueBoolType{Value: true}, metadata.Get("active"))
+	assert.Equal(t, &CollectionMetadataValueStringType{Value: "true"}, metadata.Get("label"))
+	assert.NoError(t, ValidateMetadata(metadata))
+
+	// Test case 2: bools survive a JSON round trip and stay distinct from strings
+	data, err := json.Marshal(&Collection{Metadata: metadata})
+	assert.NoError(t, err)
+	decoded := &Collection{}
+	assert.NoError(t, json.Unmarshal(data, decoded))
+	assert.True(t, decoded.Metadata.Equals(metadata))
+	assert.IsType(t, &CollectionMetadataValueBoolType{}, decoded.Metadata.Get("active"))
+	assert.IsType(t, &CollectionMetadataValueStringType{}, decoded.Metadata.Get("label"))
+
+	// Test case 3: metadata filters compare the value type
+	collection := &Collection{Metadata: metadata}
+	assert.True(t, FilterCollectionByMetadata(collection, map[string]CollectionMetadataValueType{"active": active}))
+	assert.False(t, FilterCollectionByMetadata(collection, map[string]CollectionMetadataValueType{
+		"active": &CollectionMetadataValueStringType{Value: "true"},
+	}))
+	assert.False(t, FilterCollectionByMetadata(collection, map[string]CollectionMetadataValueType{"label": active}))
+}
+
+func TestCollectionMetadataFloatEquality(t *testing.T) {
+	sum := 0.1
+	sum += 0.2
+	assert.NotEqual(t, 0.3, sum)
+
+	// Test case 1: near-equal floats match
+	value := &CollectionMetadataValueFloat64Type{Value: sum}
+	assert.True(t, value.Equals(&CollectionMetadataValueFloat64Type{Value: 0.3}))
+	assert.True(t, (&CollectionMetadataValueFloat64Type{Value: 1e12}).Equals(&CollectionMetadataValueFloat64Type{Value: 1e12 + 1e-3}))
+	assert.True(t, (&CollectionMetadataValueFloat64Type{Value: 0}).Equals(&CollectionMetadataValueFloat64Type{Value: math.Copysign(0, -1)}))
+	assert.True(t, (&CollectionMetadataValueFloat64Type{Value: 1.0}).Equals(&CollectionMetadataValueFloat64Type{Value: 1.0 + 1e-10}))
+	assert.True(t, (&CollectionMetadataValueFloat64Type{Value: 1e-12}).Equals(&CollectionMetadataValueFloat64Type{Value: 0}))
+
+	// Test case 2: clearly different floats do not match
+	assert.False(t, value.Equals(&CollectionMetadataValueFloat64Type{Value: 0.31}))
+	assert.False(t, (&CollectionMetadataValueFloat64Type{Value: 1e-6}).Equals(&CollectionMetadataValueFloat64Type{Value: 2e-6}))
+	assert.False(t, (&CollectionMetadataValueFloat64Type{Value: 1e12}).Equals(&CollectionMetadataValueFloat64Type{Value: 1e12 + 1e4}))
+	assert.False(t, (&CollectionMetadataValueFloat64Type{Value: 1.0}).Equals(&CollectionMetadataValueFloat64Type{Value: 1.0 + 1e-6}))
+	assert.False(t, (&CollectionMetadataValueFloat64Type{Value: math.NaN()}).Equals(&CollectionMetadataValueFloat64Type{Value: math.NaN()}))
+	assert.False(t, (&CollectionMetadataValueFloat64Type{Value: math.Inf(1)}).Equals(&CollectionMetadataValueFloat64Type{Value: math.MaxFloat64}))
+	assert.True(t, (&CollectionMetadataValueFloat64Type{Value: math.Inf(1)}).Equals(&CollectionMetadataValueFloat64Type{Value: math.Inf(1)}))
+
+	// Test case 3: filters and collection equality use the normalized value
+	metadata := NewCollectionMetadata[CollectionMetadataValueType]()
+	metadata.Add("ratio", value)
+	collection := &Collection{Metadata: metadata}
+	assert.True(t, FilterCollectionByMetadata(collection, map[string]CollectionMetadataValueType{
+		"ratio": &CollectionMetadataValueFloat64Type{Value: 0.3},
+	}))
+	assert.False(t, FilterCollectionByMetadata(collection, map[string]CollectionMetadataValueType{
+		"ratio": &CollectionMetadataValueFloat64Type{Value: 0.4},
+	}))
+	other := NewCollectionMetadata[CollectionMetadataValueType]()
+	other.Add("ratio", &CollectionMetadataValueFloat64Type{Value: 0.3})
+	assert.True(t, collection.Equal(&Collection{Metadata: other}))
+	assert.Equal(t, collection.Checksum(), (&Collection{Metadata: other}).Checksum())
+
+	// Test case 4: Equal implies equal checksums for near-equal floats
+	floatCollection := func(v float64) *Collection {
+		metadata := NewCollectionMetadata[CollectionMetadataValueType]()
+		metadata.Add("ratio", &CollectionMetadataValueFloat64Type{Value: v})
+		return &Collection{Metadata: metadata}
+	}
+	pairs := [][2]float64{
+		{1.0, 1.0 + 1e-16},
+		{1.0, 1.0 + 1e-10},
+		{sum, 0.3},
+		{1e12, 1e12 + 1e-3},
+		{0, math.Copysign(0, -1)},
+	}
+	for _, pair := range pairs {
+		left, right := floatCollection(pair[0]), floatCollection(pair[1])
+		assert.True(t, left.Equal(right), "%v", pair)
+		assert.Equal(t, left.Checksum(), right.Checksum(), "%v", pair)
+	}
+	assert.False(t, floatCollection(1.0).Equal(floatCollection(1.0+1e-6)))
+	assert.NotEqual(t, floatCollection(1.0).Checksum(), floatCollection(1.0+1e-6).Checksum())
+
+	// Test case 5: Equal and Checksum agree along a chain of close values
+	for i, v := 0, 0.5; i < 1000; i, v = i+1, v+FloatMetadataEpsilon/7 {
+		left, right := floatCollection(v), floatCollection(v+FloatMetadataEpsilon/7)
+		assert.Equal(t, left.Equal(right), left.Checksum() == right.Checksum(), "%v", v)
+	}
+	assert.False(t, (&CollectionMetadataValueFloat64Type{Value: math.MaxFloat64}).Equals(&CollectionMetadataValueFloat64Type{Value: math.Inf(1)}))
+}
+
+func TestNormalizeFloat(t *testing.T) {
+	sum := 0.1
+	sum += 0.2
+	assert.Equal(t, 0.3, NormalizeFloat(sum))
+	assert.Equal(t, 1.5, NormalizeFloat(1.5))
+	assert.Equal(t, 123456789012345.0, NormalizeFloat(123456789012345.0))
+	assert.Equal(t, 1234567890123460.0, NormalizeFloat(1234567890123456.0))
+	assert.True(t, math.IsNaN(NormalizeFloat(math.NaN())))
+	assert.Equal(t, math.Inf(-1), NormalizeFloat(math.Inf(-1)))
+
+	// Test case 1: floats from decoded maps are normalized before storage
+	metadata, err := CollectionMetadataFromMap(map[string]interface{}{"ratio": sum})
+	assert.NoError(t, err)
+	assert.Equal(t, &CollectionMetadataValueFloat64Type{Value: 0.3}, metadata.Get("ratio"))
+}
+
+func TestCollectionMetadataSortedKeys(t *testing.T) {
+	keys := []string{"zeta", "alpha", "mu", "beta", "omega", "gamma", "delta", "epsilon"}
+	build := func(order []string) *Collection {
+		metadata := NewCollectionMetadata[CollectionMetadataValueType]()
+		for _, key := range order {
+			metadata.Add(key, &CollectionMetadataValueInt64Type{Value: int64(len(key))})
+		}
+		return &Collection{ID: types.MustParse("00000000-0000-0000-0000-000000000001"), Metadata: metadata}
+	}
+	reversed := make([]string, len(keys))
+	for i, key := range keys {
+		reversed[len(keys)-1-i] = key
+	}
+
+	// Test case 1: keys are sorted, nil metadata has none
+	forward := build(keys)
+	assert.Equal(t, []string{"alpha", "beta", "delta", "epsilon", "gamma", "mu", "omega", "zeta"}, forward.Metadata.SortedKeys())
+	var nilMetadata *CollectionMetadata[CollectionMetadataValueType]
+	assert.Nil(t, nilMetadata.SortedKeys())
+
+	// Test case 2: serialized output does not depend on insertion order
+	backward := build(reversed)
+	forwardJSON, err := json.Marshal(forward)
+	assert.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		backwardJSON, err := json.Marshal(backward)
+		assert.NoError(t, err)
+		assert.Equal(t, string(forwardJSON), string(backwardJSON))
+		assert.Equal(t, forward.Metadata.ToKeyValueRows(forward.ID), backward.Metadata.ToKeyValueRows(backward.ID))
+		assert.Equal(t, forward.Checksum(), backward.Checksum())
+	}
+}
+
+func TestCollectionMetadataClone(t *testing.T) {
+	// Test case 1: nil metadata
+	var nilMetadata *CollectionMetadata[CollectionMetadataValueType]
+	assert.Nil(t, nilMetadata.Clone())
+
+	// Test case 2: mutating the clone leaves the source untouched
+	source := NewCollectionMetadata[CollectionMetadataValueType]()
+	source.Add("string", &CollectionMetadataValueStringType{Value: "value"})
+	source.Add("int", &CollectionMetadataValueInt64Type{Value: 1})
+	source.Add("list", &CollectionMetadataValueStringListType{Value: []string{"a", "b"}})
+	source.Add("none", &CollectionMetadataValueNoneType{})
+	clone := source.Clone()
+	assert.True(t, clone.Equals(source))
+
+	clone.Add("new", &CollectionMetadataValueBoolType{Value: true})
+	clone.Remove("string")
+	clone.Get("int").(*CollectionMetadataValueInt64Type).Value = 2
+	clone.Get("list").(*CollectionMetadataValueStringListType).Value[0] = "changed"
+
+	assert.Len(t, source.Metadata, 4)
+	str, ok := source.GetString("string")
+	assert.True(t, ok)
+	assert.Equal(t, "value", str)
+	i, _ := source.GetInt("int")
+	assert.Equal(t, int64(1), i)
+	assert.Equal(t, []string{"a", "b"}, source.Get("list").(*CollectionMetadataValueStringListType).Value)
+	_, ok = source.Metadata["new"]
+	assert.False(t, ok)
+
+	// Test case 3: nil values are preserved
+	withNil := NewCollectionMetadata[CollectionMetadataValueType]()
+	withNil.Add("nil", nil)
+	assert.Contains(t, withNil.Clone().Metadata, "nil")
+}
+
+func TestCollectionMetadataFromMap(t *testing.T) {
+	// Test case 1: nil and empty input
+	metadata, err := CollectionMetadataFromMap(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, metadata)
+	metadata, err = CollectionMetadataFromMap(map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Nil(t, metadata)
+
+	// Test case 2: every supported type
+	metadata, err = CollectionMetadataFromMap(map[string]interface{}{
+		"string":    "value",
+		"int":       1,
+		"int32":     int32(2),
+		"int64":     int64(3),
+		"float":     4.5,
+		"bool":      true,
+		"list":      []string{"a", "b"},
+		"json_list": []interface{}{"c"},
+		"none":      nil,
+	})
+	assert.NoError(t, err)
+	expected := NewCollectionMetadata[CollectionMetadataValueType]()
+	expected.Add("string", &CollectionMetadataValueStringType{Value: "value"})
+	expected.Add("int", &CollectionMetadataValueInt64Type{Value: 1})
+	expected.Add("int32", &CollectionMetadataValueInt64Type{Value: 2})
+	expected.Add("int64", &CollectionMetadataValueInt64Type{Value: 3})
+	expected.Add("float", &CollectionMetadataValueFloat64Type{Value: 4.5})
+	expected.Add("bool", &CollectionMetadataValueBoolType{Value: true})
+	expected.Add("list", &CollectionMetadataValueStringListType{Value: []string{"a", "b"}})
+	expected.Add("json_list", &CollectionMetadataValueStringListType{Value: []string{"c"}})
+	expected.Add("none", &CollectionMetadataValueNoneType{})
+	assert.True(t, metadata.Equals(expected))
+
+	// Test case 3: unsupported types
+	for _, value := range []interface{}{
+		float32(1),
+		map[string]interface{}{"nested": 1},
+		[]interface{}{"a", 1},
+		struct{}{},
+	} {
+		metadata, err = CollectionMetadataFromMap(map[string]interface{}{"bad": value})
+		assert.Nil(t, metadata)
+		var unsupported *UnsupportedMetadataValueError
+		assert.ErrorAs(t, err, &unsupported)
+		assert.Equal(t, "bad", unsupported.Key)
+		assert.ErrorIs(t, err, common.ErrUnknownCollectionMetadataType)
+	}
+}
+
+func TestCollectionMetadataFoldGetters(t *testing.T) {
+	metadata := NewCollectionMetadata[CollectionMetadataValueType]()
+	metadata.Add("Source", &CollectionMetadataValueStringType{Value: "web"})
+	metadata.Add("COUNT", &CollectionMetadataValueInt64Type{Value: 3})
+	metadata.Add("Score", &CollectionMetadataValueFloat64Type{Value: 0.5})
+	metadata.Add("Enabled", &CollectionMetadataValueBoolType{Value: true})
+
+	// Test case 1: case-insensitive hits
+	source, ok := metadata.GetStringFold("source")
+	assert.True(t, ok)
+	assert.Equal(t, "web", source)
+	count, ok := metadata.GetIntFold("count")
+	assert.True(t, ok)
+	assert.Equal(t, int64(3), count)
+	score, ok := metadata.GetFloatFold("SCORE")
+	assert.True(t, ok)
+	assert.Equal(t, 0.5, score)
+	enabled, ok := metadata.GetBoolFold("enabled")
+	assert.True(t, ok)
+	assert.True(t, enabled)
+
+	// The exact-match getters are unchanged.
+	_, ok = metadata.GetString("source")
+	assert.False(t, ok)
+
+	// Test case 2: no match
+	_, ok = metadata.GetStringFold("missing")
+	assert.False(t, ok)
+	_, ok = metadata.GetIntFold("source")
+	assert.False(t, ok)
+	var nilMetadata *CollectionMetadata[CollectionMetadataValueType]
+	_, ok = nilMetadata.GetStringFold("source")
+	assert.False(t, ok)
+
+	// Test case 3: colliding keys resolve to the lexicographically first
+	colliding := NewCollectionMetadata[CollectionMetadataValueType]()
+	colliding.Add("source", &CollectionMetadataValueStringType{Value: "lower"})
+	colliding.Add("SOURCE", &CollectionMetadataValueStringType{Value: "upper"})
+	colliding.Add("Source", &CollectionMetadataValueStringType{Value: "title"})
+	for i := 0; i < 10; i++ {
+		value, ok := colliding.GetStringFold("source")
+		assert.True(t, ok)
+		assert.Equal(t, "upper", value)
+	}
+}
+
+func TestCollectionMetadataValueKind(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  CollectionMetadataValueType
+		kind   MetadataValueKind
+		string string
+	}{
+		{name: "string", value: &CollectionMetadataValueStringType{Value: "a b"}, kind: KindString, string: `string("a b")`},
+		{name: "int", value: &CollectionMetadataValueInt64Type{Value: 3}, kind: KindInt, string: "int(3)"},
+		{name: "float", value: &CollectionMetadataValueFloat64Type{Value: 1.5}, kind: KindFloat, string: "float(1.5)"},
+		{name: "bool", value: &CollectionMetadataValueBoolType{Value: true}, kind: KindBool, string: "bool(true)"},
+		{name: "list", value: &CollectionMetadataValueStringListType{Value: []string{"a", "b"}}, kind: KindList, string: `list(["a" "b"])`},
+		{name: "none", value: &CollectionMetadataValueNoneType{}, kind: KindNone, string: "none"},
+		{name: "delete", value: &CollectionMetadataValueDeleteType{}, kind: KindDelete, string: "delete"},
+		{name: "nil string", value: (*CollectionMetadataValueStringType)(nil), kind: KindString, string: "string(nil)"},
+		{name: "nil int", value: (*CollectionMetadataValueInt64Type)(nil), kind: KindInt, string: "int(nil)"},
+		{name: "nil float", value: (*CollectionMetadataValueFloat64Type)(nil), kind: KindFloat, string: "float(nil)"},
+		{name: "nil bool", value: (*CollectionMetadataValueBoolType)(nil), kind: KindBool, string: "bool(nil)"},
+		{name: "nil list", value: (*CollectionMetadataValueStringListType)(nil), kind: KindList, string: "list(nil)"},
+		{name: "nil none", value: (*CollectionMetadataValueNoneType)(nil), kind: KindNone, string: "none"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.kind, tt.value.Kind())
+			assert.Equal(t, tt.string, tt.value.String())
+		})
+	}
+
+	assert.Equal(t, "int", KindInt.String())
+	assert.Equal(t, "unknown", KindUnknown.String())
+	assert.Equal(t, "unknown", MetadataValueKind(99).String())
+}
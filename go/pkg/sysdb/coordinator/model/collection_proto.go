@@ -0,0 +1,123 @@
+package model
+
+import (
+	"fmt"
+
+	"github.com/chroma-core/chroma/go/pkg/common"
+	"github.com/chroma-core/chroma/go/pkg/proto/coordinatorpb"
+	"github.com/chroma-core/chroma/go/pkg/types"
+)
+
+// ToProto converts the collection to its protobuf message. The message only
+// carries string, int, float and bool metadata; other value kinds are
+// dropped. A nil collection converts to nil.
+func (c *Collection) ToProto() *coordinatorpb.Collection {
+	if c == nil {
+		return nil
+	}
+	collectionpb := &coordinatorpb.Collection{
+		Id:                   c.ID.String(),
+		Name:                 c.Name,
+		ConfigurationJsonStr: c.ConfigurationJsonStr,
+		Dimension:            cloneInt32(c.Dimension),
+		Tenant:               c.TenantID,
+		Database:             c.DatabaseName,
+		LogPosition:          c.LogPosition,
+		Version:              c.Version,
+	}
+	collectionpb.Metadata = CollectionMetadataToProto(c.Metadata)
+	return collectionpb
+}
+
+// CollectionMetadataToProto converts metadata to its protobuf message,
+// dropping value kinds the message cannot carry. Nil metadata converts to nil.
+func CollectionMetadataToProto(m *CollectionMetadata[CollectionMetadataValueType]) *coordinatorpb.UpdateMetadata {
+	if m == nil {
+		return nil
+	}
+	metadatapb := &coordinatorpb.UpdateMetadata{
+		Metadata: make(map[string]*coordinatorpb.UpdateMetadataValue, len(m.Metadata)),
+	}
+	for key, value := range m.Metadata {
+		if valuepb := collectionMetadataValueToProto(value); valuepb != nil {
+			metadatapb.Metadata[key] = valuepb
+		}
+	}
+	return metadatapb
+}
+
+func collectionMetadataValueToProto(value CollectionMetadataValueType) *coordinatorpb.UpdateMetadataValue {
+	switch v := value.(type) {
+	case *CollectionMetadataValueStringType:
+		return &coordinatorpb.UpdateMetadataValue{Value: &coordinatorpb.UpdateMetadataValue_StringValue{StringValue: v.Value}}
+	case *CollectionMetadataValueInt64Type:
+		return &coordinatorpb.UpdateMetadataValue{Value: &coordinatorpb.UpdateMetadataValue_IntValue{IntValue: v.Value}}
+	case *CollectionMetadataValueFloat64Type:
+		return &coordinatorpb.UpdateMetadataValue{Value: &coordinatorpb.UpdateMetadataValue_FloatValue{FloatValue: v.Value}}
+	case *CollectionMetadataValueBoolType:
+		return &coordinatorpb.UpdateMetadataValue{Value: &coordinatorpb.UpdateMetadataValue_BoolValue{BoolValue: v.Value}}
+	default:
+		return nil
+	}
+}
+
+// CollectionFromProto converts a protobuf collection to the model. A nil
+// message converts to a nil collection. A malformed ID returns an
+// *InvalidCollectionIDError.
+func CollectionFromProto(p *coordinatorpb.Collection) (*Collection, error) {
+	if p == nil {
+		return nil, nil
+	}
+	id, err := types.ParseUniqueID(p.Id)
+	if err != nil {
+		return nil, &InvalidCollectionIDError{ID: p.Id}
+	}
+	collection := &Collection{
+		ID:                   id,
+		Name:                 p.Name,
+		ConfigurationJsonStr: p.ConfigurationJsonStr,
+		Dimension:            cloneInt32(p.Dimension),
+		TenantID:             p.Tenant,
+		DatabaseName:         p.Database,
+		LogPosition:          p.LogPosition,
+		Version:              p.Version,
+	}
+	collection.Metadata, err = CollectionMetadataFromProto(p.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	return collection, nil
+}
+
+// CollectionMetadataFromProto converts a protobuf metadata message to the
+// model. A nil message converts to nil metadata. A value of an unsupported
+// kind returns an error wrapping common.ErrUnknownCollectionMetadataType.
+func CollectionMetadataFromProto(metadatapb *coordinatorpb.UpdateMetadata) (*CollectionMetadata[CollectionMetadataValueType], error) {
+	if metadatapb == nil {
+		return nil, nil
+	}
+	metadata := NewCollectionMetadata[CollectionMetadataValueType]()
+	for key, valuepb := range metadatapb.Metadata {
+		value, err := collectionMetadataValueFromProto(valuepb)
+		if err != nil {
+			return nil, fmt.Errorf("metadata key %q: %w", key, err)
+		}
+		metadata.Add(key, value)
+	}
+	return metadata, nil
+}
+
+func collectionMetadataValueFromProto(valuepb *coordinatorpb.UpdateMetadataValue) (CollectionMetadataValueType, error) {
+	switch v := valuepb.GetValue().(type) {
+	case *coordinatorpb.UpdateMetadataValue_StringValue:
+		return &CollectionMetadataValueStringType{Value: v.StringValue}, nil
+	case *coordinatorpb.UpdateMetadataValue_IntValue:
+		return &CollectionMetadataValueInt64Type{Value: v.IntValue}, nil
+	case *coordinatorpb.UpdateMetadataValue_FloatValue:
+		return &CollectionMetadataValueFloat64Type{Value: v.FloatValue}, nil
+	case *coordinatorpb.UpdateMetadataValue_BoolValue:
+		return &CollectionMetadataValueBoolType{Value: v.BoolValue}, nil
+	default:
+		return nil, common.ErrUnknownCollectionMetadataType
+	}
+}
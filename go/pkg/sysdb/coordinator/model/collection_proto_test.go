@@ -0,0 +1,75 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/chroma-core/chroma/go/pkg/common"
+	"github.com/chroma-core/chroma/go/pkg/proto/coordinatorpb"
+	"github.com/chroma-core/chroma/go/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectionProtoRoundTrip(t *testing.T) {
+	mixed := NewCollectionMetadata[CollectionMetadataValueType]()
+	mixed.Add("string", &CollectionMetadataValueStringType{Value: "1"})
+	mixed.Add("int", &CollectionMetadataValueInt64Type{Value: 1})
+	mixed.Add("float", &CollectionMetadataValueFloat64Type{Value: 1})
+	mixed.Add("bool", &CollectionMetadataValueBoolType{Value: true})
+
+	collections := []*Collection{
+		{ID: types.NewUniqueID()},
+		{ID: types.NewUniqueID(), Name: "no dimension", TenantID: "tenant", DatabaseName: "database"},
+		{ID: types.NewUniqueID(), Name: "empty metadata", Metadata: NewCollectionMetadata[CollectionMetadataValueType]()},
+		{
+			ID:                   types.NewUniqueID(),
+			Name:                 "everything",
+			ConfigurationJsonStr: `{"a":1}`,
+			Dimension:            int32Ptr(128),
+			Metadata:             mixed,
+			TenantID:             "tenant",
+			DatabaseName:         "database",
+			LogPosition:          10,
+			Version:              2,
+		},
+	}
+	for _, collection := range collections {
+		decoded, err := CollectionFromProto(collection.ToProto())
+		assert.NoError(t, err)
+		assert.True(t, collection.Equal(decoded), collection.Name)
+	}
+
+	// Value types survive the round trip.
+	decoded, err := CollectionFromProto(collections[3].ToProto())
+	assert.NoError(t, err)
+	_, ok := decoded.Metadata.GetInt("int")
+	assert.True(t, ok)
+	_, ok = decoded.Metadata.GetFloat("float")
+	assert.True(t, ok)
+	_, ok = decoded.Metadata.GetString("string")
+	assert.True(t, ok)
+}
+
+func TestCollectionFromProto(t *testing.T) {
+	// Test case 1: nil converts to nil
+	var nilCollection *Collection
+	assert.Nil(t, nilCollection.ToProto())
+	collection, err := CollectionFromProto(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, collection)
+
+	// Test case 2: malformed id
+	_, err = CollectionFromProto(&coordinatorpb.Collection{Id: "not-a-uuid"})
+	var idErr *InvalidCollectionIDError
+	assert.ErrorAs(t, err, &idErr)
+	assert.Equal(t, "not-a-uuid", idErr.ID)
+	assert.ErrorIs(t, err, common.ErrCollectionIDFormat)
+
+	// Test case 3: metadata value without a type
+	_, err = CollectionFromProto(&coordinatorpb.Collection{
+		Id: types.NewUniqueID().String(),
+		Metadata: &coordinatorpb.UpdateMetadata{
+			Metadata: map[string]*coordinatorpb.UpdateMetadataValue{"key": {}},
+		},
+	})
+	assert.ErrorIs(t, err, common.ErrUnknownCollectionMetadataType)
+}
@@ -0,0 +1,36 @@
+package model
+
+// RedactedMetadataValue replaces masked metadata values in Redacted.
+const RedactedMetadataValue = "***"
+
+// RedactedMetadataKeys lists metadata keys masked by Redacted in addition to
+// the reserved keys. Append to it at init time to mask further keys.
+var RedactedMetadataKeys []string
+
+// Redacted returns a clone of the collection that is safe to log: the values
+// of reserved metadata keys and of RedactedMetadataKeys are replaced with
+// RedactedMetadataValue. c itself is not modified.
+func (c *Collection) Redacted() *Collection {
+	redacted := c.Clone()
+	if redacted == nil || redacted.Metadata == nil {
+		return redacted
+	}
+	for key := range redacted.Metadata.Metadata {
+		if isRedactedKey(key) {
+			redacted.Metadata.Metadata[key] = &CollectionMetadataValueStringType{Value: RedactedMetadataValue}
+		}
+	}
+	return redacted
+}
+
+func isRedactedKey(key string) bool {
+	if IsReservedKey(key) {
+		return true
+	}
+	for _, redactedKey := range RedactedMetadataKeys {
+		if key == redactedKey {
+			return true
+		}
+	}
+	return false
+}
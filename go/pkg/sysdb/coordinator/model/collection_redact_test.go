@@ -0,0 +1,40 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectionRedacted(t *testing.T) {
+	metadata := NewCollectionMetadata[CollectionMetadataValueType]()
+	metadata.Add("chroma:embedding_function", &CollectionMetadataValueStringType{Value: `{"api_key":"secret"}`})
+	metadata.Add("chroma:retries", &CollectionMetadataValueInt64Type{Value: 3})
+	metadata.Add("owner", &CollectionMetadataValueStringType{Value: "alice"})
+	metadata.Add("token", &CollectionMetadataValueStringType{Value: "abc"})
+	collection := &Collection{Name: "collection", Metadata: metadata}
+	source := collection.Clone()
+
+	// Test case 1: reserved values are masked and others preserved
+	redacted := collection.Redacted()
+	assert.Equal(t, &CollectionMetadataValueStringType{Value: RedactedMetadataValue}, redacted.Metadata.Get("chroma:embedding_function"))
+	assert.Equal(t, &CollectionMetadataValueStringType{Value: RedactedMetadataValue}, redacted.Metadata.Get("chroma:retries"))
+	assert.Equal(t, &CollectionMetadataValueStringType{Value: "alice"}, redacted.Metadata.Get("owner"))
+	assert.Equal(t, &CollectionMetadataValueStringType{Value: "abc"}, redacted.Metadata.Get("token"))
+	assert.Equal(t, "collection", redacted.Name)
+
+	// Test case 2: the source is untouched
+	assert.True(t, source.Equal(collection))
+
+	// Test case 3: RedactedMetadataKeys extends the masked keys
+	RedactedMetadataKeys = append(RedactedMetadataKeys, "token")
+	defer func() { RedactedMetadataKeys = nil }()
+	redacted = collection.Redacted()
+	assert.Equal(t, &CollectionMetadataValueStringType{Value: RedactedMetadataValue}, redacted.Metadata.Get("token"))
+	assert.Equal(t, &CollectionMetadataValueStringType{Value: "alice"}, redacted.Metadata.Get("owner"))
+	assert.True(t, source.Equal(collection))
+
+	// Test case 4: nil collection and nil metadata
+	assert.Nil(t, (*Collection)(nil).Redacted())
+	assert.Nil(t, (&Collection{Name: "bare"}).Redacted().Metadata)
+}
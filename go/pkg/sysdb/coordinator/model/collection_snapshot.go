@@ -0,0 +1,32 @@
+package model
+
+import "github.com/chroma-core/chroma/go/pkg/types"
+
+// CollectionSnapshot is a collection as of a version and log position, used
+// for point-in-time reads. Collection is a private copy that later mutations
+// of the source collection do not affect.
+type CollectionSnapshot struct {
+	Collection  *Collection
+	Version     int32
+	LogPosition int64
+	CapturedAt  types.Timestamp
+}
+
+// SnapshotCollection captures c as of version and logPos at now.
+func SnapshotCollection(c *Collection, version int32, logPos int64, now types.Timestamp) *CollectionSnapshot {
+	return &CollectionSnapshot{
+		Collection:  c.Clone(),
+		Version:     version,
+		LogPosition: logPos,
+		CapturedAt:  now,
+	}
+}
+
+// IsOlderThan reports whether s precedes other, comparing by version and then
+// by log position. CapturedAt is not considered.
+func (s *CollectionSnapshot) IsOlderThan(other *CollectionSnapshot) bool {
+	if s.Version != other.Version {
+		return s.Version < other.Version
+	}
+	return s.LogPosition < other.LogPosition
+}
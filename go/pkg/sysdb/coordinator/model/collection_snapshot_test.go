@@ -0,0 +1,53 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/chroma-core/chroma/go/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshotCollection(t *testing.T) {
+	metadata := NewCollectionMetadata[CollectionMetadataValueType]()
+	metadata.Add("key", &CollectionMetadataValueStringType{Value: "value"})
+	collection := &Collection{ID: types.NewUniqueID(), Name: "collection", Metadata: metadata, Dimension: int32Ptr(3)}
+
+	// Test case 1: the snapshot records its position
+	snapshot := SnapshotCollection(collection, 2, 10, 100)
+	assert.Equal(t, int32(2), snapshot.Version)
+	assert.Equal(t, int64(10), snapshot.LogPosition)
+	assert.Equal(t, types.Timestamp(100), snapshot.CapturedAt)
+	assert.True(t, collection.Equal(snapshot.Collection))
+
+	// Test case 2: later mutations do not alter the snapshot
+	collection.Name = "renamed"
+	*collection.Dimension = 4
+	collection.Metadata.Add("key", &CollectionMetadataValueStringType{Value: "changed"})
+	assert.Equal(t, "collection", snapshot.Collection.Name)
+	assert.Equal(t, int32(3), *snapshot.Collection.Dimension)
+	value, _ := snapshot.Collection.Metadata.GetString("key")
+	assert.Equal(t, "value", value)
+
+	// Test case 3: nil collection
+	assert.Nil(t, SnapshotCollection(nil, 1, 0, 0).Collection)
+}
+
+func TestCollectionSnapshotIsOlderThan(t *testing.T) {
+	tests := []struct {
+		name     string
+		s        *CollectionSnapshot
+		other    *CollectionSnapshot
+		expected bool
+	}{
+		{name: "lower version", s: &CollectionSnapshot{Version: 1, LogPosition: 20}, other: &CollectionSnapshot{Version: 2, LogPosition: 10}, expected: true},
+		{name: "higher version", s: &CollectionSnapshot{Version: 2, LogPosition: 10}, other: &CollectionSnapshot{Version: 1, LogPosition: 20}, expected: false},
+		{name: "same version lower log position", s: &CollectionSnapshot{Version: 1, LogPosition: 10}, other: &CollectionSnapshot{Version: 1, LogPosition: 20}, expected: true},
+		{name: "same version higher log position", s: &CollectionSnapshot{Version: 1, LogPosition: 20}, other: &CollectionSnapshot{Version: 1, LogPosition: 10}, expected: false},
+		{name: "same position", s: &CollectionSnapshot{Version: 1, LogPosition: 10, CapturedAt: 1}, other: &CollectionSnapshot{Version: 1, LogPosition: 10, CapturedAt: 2}, expected: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.s.IsOlderThan(tt.other))
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package model
+
+import "fmt"
+
+// CollectionState tracks whether a collection is queryable. The zero value is
+// CollectionStateReady so collections loaded from storage without a state are
+// treated as ready.
+type CollectionState int32
+
+const (
+	CollectionStateReady CollectionState = iota
+	CollectionStateCreating
+	CollectionStateDeleting
+)
+
+var collectionStateNames = map[CollectionState]string{
+	CollectionStateReady:    "ready",
+	CollectionStateCreating: "creating",
+	CollectionStateDeleting: "deleting",
+}
+
+func (s CollectionState) String() string {
+	if name, ok := collectionStateNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("unknown(%d)", int32(s))
+}
+
+func (s CollectionState) MarshalText() ([]byte, error) {
+	if _, ok := collectionStateNames[s]; !ok {
+		return nil, fmt.Errorf("unknown collection state %d", int32(s))
+	}
+	return []byte(s.String()), nil
+}
+
+func (s *CollectionState) UnmarshalText(text []byte) error {
+	for state, name := range collectionStateNames {
+		if name == string(text) {
+			*s = state
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown collection state %q", string(text))
+}
+
+func (c *Collection) CanQuery() bool {
+	return c.State == CollectionStateReady
+}
+
+// SegmentsReady reports whether the collection is ready and has at least one
+// segment. A collection without stats has no known segments.
+func (c *Collection) SegmentsReady() bool {
+	return c != nil && c.State == CollectionStateReady && c.Stats != nil && c.Stats.SegmentCount > 0
+}
+
+// AllReady reports whether every collection's segments are ready. An empty
+// slice is ready; a nil collection is not.
+func AllReady(collections []*Collection) bool {
+	for _, collection := range collections {
+		if !collection.SegmentsReady() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,65 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/chroma-core/chroma/go/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectionStateString(t *testing.T) {
+	assert.Equal(t, "ready", CollectionStateReady.String())
+	assert.Equal(t, "creating", CollectionStateCreating.String())
+	assert.Equal(t, "deleting", CollectionStateDeleting.String())
+	assert.Equal(t, "unknown(42)", CollectionState(42).String())
+
+	for _, state := range []CollectionState{CollectionStateReady, CollectionStateCreating, CollectionStateDeleting} {
+		text, err := state.MarshalText()
+		assert.NoError(t, err)
+		var parsed CollectionState
+		assert.NoError(t, parsed.UnmarshalText(text))
+		assert.Equal(t, state, parsed)
+	}
+	_, err := CollectionState(42).MarshalText()
+	assert.Error(t, err)
+	var parsed CollectionState
+	assert.Error(t, parsed.UnmarshalText([]byte("unknown")))
+}
+
+func TestCollectionCanQuery(t *testing.T) {
+	assert.True(t, (&Collection{}).CanQuery())
+	assert.True(t, (&Collection{State: CollectionStateReady}).CanQuery())
+	assert.False(t, (&Collection{State: CollectionStateCreating}).CanQuery())
+	assert.False(t, (&Collection{State: CollectionStateDeleting}).CanQuery())
+
+	creating := &Collection{ID: types.NewUniqueID(), State: CollectionStateCreating}
+	ready := &Collection{ID: types.NewUniqueID(), State: CollectionStateReady}
+	assert.True(t, FilterCollection(creating, types.NilUniqueID(), nil))
+	assert.False(t, FilterCollection(creating, types.NilUniqueID(), nil, WithReadyOnly()))
+	assert.True(t, FilterCollection(ready, types.NilUniqueID(), nil, WithReadyOnly()))
+	assert.Len(t, mustFilterCollections(t, []*Collection{creating, ready}, CollectionListOptions{ReadyOnly: true}), 1)
+}
+
+func TestCollectionSegmentsReady(t *testing.T) {
+	ready := &Collection{State: CollectionStateReady, Stats: &CollectionStats{SegmentCount: 3}}
+	creating := &Collection{State: CollectionStateCreating, Stats: &CollectionStats{SegmentCount: 3}}
+	empty := &Collection{State: CollectionStateReady, Stats: &CollectionStats{}}
+
+	// Test case 1: a ready collection with segments
+	assert.True(t, ready.SegmentsReady())
+
+	// Test case 2: a creating collection is not ready
+	assert.False(t, creating.SegmentsReady())
+
+	// Test case 3: ready but without segments or stats
+	assert.False(t, empty.SegmentsReady())
+	assert.False(t, (&Collection{State: CollectionStateReady}).SegmentsReady())
+	assert.False(t, (*Collection)(nil).SegmentsReady())
+
+	// Test case 4: AllReady requires every collection to be ready
+	assert.True(t, AllReady(nil))
+	assert.True(t, AllReady([]*Collection{ready, ready}))
+	assert.False(t, AllReady([]*Collection{ready, creating}))
+	assert.False(t, AllReady([]*Collection{ready, empty}))
+	assert.False(t, AllReady([]*Collection{ready, nil}))
+}
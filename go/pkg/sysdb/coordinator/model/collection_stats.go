@@ -0,0 +1,35 @@
+package model
+
+// CollectionStats are approximate counters maintained for listing. They may
+// lag behind the collection's contents.
+type CollectionStats struct {
+	RecordCount      uint64
+	SegmentCount     uint32
+	LogicalSizeBytes uint64
+}
+
+func (s *CollectionStats) Clone() *CollectionStats {
+	if s == nil {
+		return nil
+	}
+	clone := *s
+	return &clone
+}
+
+// RecordCount returns the collection's record count, or 0 when it has no
+// stats.
+func (c *Collection) RecordCount() uint64 {
+	if c.Stats == nil {
+		return 0
+	}
+	return c.Stats.RecordCount
+}
+
+// EstimateSizeBytes estimates the storage needed for recordCount float32
+// vectors of the given dimension. A non-positive dimension estimates 0.
+func EstimateSizeBytes(recordCount uint64, dimension int32) uint64 {
+	if dimension <= 0 {
+		return 0
+	}
+	return recordCount * uint64(dimension) * 4
+}
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateSizeBytes(t *testing.T) {
+	assert.Equal(t, uint64(0), EstimateSizeBytes(0, 128))
+	assert.Equal(t, uint64(512), EstimateSizeBytes(1, 128))
+	assert.Equal(t, uint64(1000*1536*4), EstimateSizeBytes(1000, 1536))
+	assert.Equal(t, uint64(0), EstimateSizeBytes(1000, 0))
+	assert.Equal(t, uint64(0), EstimateSizeBytes(1000, -1))
+}
+
+func TestFilterCollectionsSortByRecordCount(t *testing.T) {
+	collections := []*Collection{
+		{Name: "large", Stats: &CollectionStats{RecordCount: 300}},
+		{Name: "no_stats"},
+		{Name: "small", Stats: &CollectionStats{RecordCount: 100}},
+		{Name: "empty", Stats: &CollectionStats{}},
+		{Name: "medium", Stats: &CollectionStats{RecordCount: 200}},
+	}
+
+	// Test case 1: ascending, with nil stats sorting as zero
+	result := mustFilterCollections(t, collections, CollectionListOptions{SortBy: SortByRecordCount})
+	names := collectionNames(result)
+	assert.ElementsMatch(t, []string{"no_stats", "empty"}, names[:2])
+	assert.Equal(t, []string{"small", "medium", "large"}, names[2:])
+
+	// Test case 2: descending
+	result = mustFilterCollections(t, collections, CollectionListOptions{SortBy: SortByRecordCount, Descending: true})
+	assert.Equal(t, []string{"large", "medium", "small"}, collectionNames(result)[:3])
+}
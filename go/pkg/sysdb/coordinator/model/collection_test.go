@@ -0,0 +1,1234 @@
+package model
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/chroma-core/chroma/go/pkg/common"
+	"github.com/chroma-core/chroma/go/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterCollectionByMetadata(t *testing.T) {
+	metadata := NewCollectionMetadata[CollectionMetadataValueType]()
+	metadata.Add("str", &CollectionMetadataValueStringType{Value: "3"})
+	metadata.Add("int", &CollectionMetadataValueInt64Type{Value: 3})
+	metadata.Add("float", &CollectionMetadataValueFloat64Type{Value: 3.5})
+	metadata.Add("bool", &CollectionMetadataValueBoolType{Value: true})
+	collection := &Collection{ID: types.NewUniqueID(), Name: "collection", Metadata: metadata}
+	noMetadata := &Collection{ID: types.NewUniqueID(), Name: "collection"}
+
+	tests := []struct {
+		name       string
+		collection *Collection
+		predicates map[string]CollectionMetadataValueType
+		expected   bool
+	}{
+		{
+			name:       "no predicates matches",
+			collection: collection,
+			predicates: nil,
+			expected:   true,
+		},
+		{
+			name:       "no predicates matches nil metadata",
+			collection: noMetadata,
+			predicates: map[string]CollectionMetadataValueType{},
+			expected:   true,
+		},
+		{
+			name:       "nil metadata does not match",
+			collection: noMetadata,
+			predicates: map[string]CollectionMetadataValueType{"str": &CollectionMetadataValueStringType{Value: "3"}},
+			expected:   false,
+		},
+		{
+			name:       "missing key",
+			collection: collection,
+			predicates: map[string]CollectionMetadataValueType{"missing": &CollectionMetadataValueStringType{Value: "3"}},
+			expected:   false,
+		},
+		{
+			name:       "int does not match string",
+			collection: collection,
+			predicates: map[string]CollectionMetadataValueType{"str": &CollectionMetadataValueInt64Type{Value: 3}},
+			expected:   false,
+		},
+		{
+			name:       "string does not match int",
+			collection: collection,
+			predicates: map[string]CollectionMetadataValueType{"int": &CollectionMetadataValueStringType{Value: "3"}},
+			expected:   false,
+		},
+		{
+			name:       "float does not match int",
+			collection: collection,
+			predicates: map[string]CollectionMetadataValueType{"int": &CollectionMetadataValueFloat64Type{Value: 3}},
+			expected:   false,
+		},
+		{
+			name:       "different value",
+			collection: collection,
+			predicates: map[string]CollectionMetadataValueType{"int": &CollectionMetadataValueInt64Type{Value: 4}},
+			expected:   false,
+		},
+		{
+			name:       "all predicates match",
+			collection: collection,
+			predicates: map[string]CollectionMetadataValueType{
+				"str":   &CollectionMetadataValueStringType{Value: "3"},
+				"int":   &CollectionMetadataValueInt64Type{Value: 3},
+				"float": &CollectionMetadataValueFloat64Type{Value: 3.5},
+				"bool":  &CollectionMetadataValueBoolType{Value: true},
+			},
+			expected: true,
+		},
+		{
+			name:       "one of several predicates mismatches",
+			collection: collection,
+			predicates: map[string]CollectionMetadataValueType{
+				"str":  &CollectionMetadataValueStringType{Value: "3"},
+				"bool": &CollectionMetadataValueBoolType{Value: false},
+			},
+			expected: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, FilterCollectionByMetadata(tt.collection, tt.predicates))
+		})
+	}
+}
+
+func TestIsDeleted(t *testing.T) {
+	zero := types.Timestamp(0)
+	deletedAt := types.Timestamp(100)
+
+	assert.False(t, IsDeleted(nil))
+	assert.False(t, IsDeleted(&Collection{}))
+	assert.False(t, IsDeleted(&Collection{DeletedAt: &zero}))
+	assert.True(t, IsDeleted(&Collection{DeletedAt: &deletedAt}))
+}
+
+func TestFilterCollectionSoftDeleted(t *testing.T) {
+	deletedAt := types.Timestamp(100)
+	live := &Collection{ID: types.NewUniqueID(), Name: "live"}
+	deleted := &Collection{ID: types.NewUniqueID(), Name: "deleted", DeletedAt: &deletedAt}
+
+	// Test case 1: soft deleted collections are filtered out by default
+	assert.True(t, FilterCollection(live, types.NilUniqueID(), nil))
+	assert.False(t, FilterCollection(deleted, types.NilUniqueID(), nil))
+	assert.False(t, FilterCollection(deleted, deleted.ID, nil))
+
+	// Test case 2: soft deleted collections are returned when requested
+	assert.True(t, FilterCollection(live, types.NilUniqueID(), nil, WithIncludeDeleted()))
+	assert.True(t, FilterCollection(deleted, types.NilUniqueID(), nil, WithIncludeDeleted()))
+	assert.True(t, FilterCollection(deleted, deleted.ID, nil, WithIncludeDeleted()))
+
+	// Test case 3: other filters still apply to soft deleted collections
+	name := "live"
+	assert.False(t, FilterCollection(deleted, types.NilUniqueID(), &name, WithIncludeDeleted()))
+}
+
+func TestUpdateCollectionValidateDimension(t *testing.T) {
+	dimension := int32(128)
+	sameDimension := int32(128)
+	otherDimension := int32(256)
+
+	// Test case 1: nil -> value is allowed
+	update := &UpdateCollection{TenantID: "tenant", DatabaseName: "database", Dimension: &dimension}
+	assert.NoError(t, update.Validate(&Collection{TenantID: "tenant", DatabaseName: "database"}))
+
+	// Test case 2: value -> same value is allowed
+	update = &UpdateCollection{TenantID: "tenant", DatabaseName: "database", Dimension: &sameDimension}
+	assert.NoError(t, update.Validate(&Collection{TenantID: "tenant", DatabaseName: "database", Dimension: &dimension}))
+
+	// Test case 3: no dimension in the update is allowed
+	update = &UpdateCollection{TenantID: "tenant", DatabaseName: "database"}
+	assert.NoError(t, update.Validate(&Collection{TenantID: "tenant", DatabaseName: "database", Dimension: &dimension}))
+
+	// Test case 4: an invalid name is rejected
+	name := "!"
+	update = &UpdateCollection{TenantID: "tenant", DatabaseName: "database", Name: &name}
+	var nameErr *InvalidNameError
+	assert.ErrorAs(t, update.Validate(&Collection{TenantID: "tenant", DatabaseName: "database"}), &nameErr)
+
+	// Test case 5: value -> different value is rejected
+	update = &UpdateCollection{TenantID: "tenant", DatabaseName: "database", Dimension: &otherDimension}
+	err := update.Validate(&Collection{TenantID: "tenant", DatabaseName: "database", Dimension: &dimension})
+	var mismatch *DimensionMismatchError
+	assert.ErrorAs(t, err, &mismatch)
+	assert.Equal(t, int32(128), mismatch.Existing)
+	assert.Equal(t, int32(256), mismatch.Requested)
+	assert.Contains(t, err.Error(), "128")
+	assert.Contains(t, err.Error(), "256")
+
+	// Test case 6: zero or negative dimensions are rejected, even on a
+	// collection without one, and are never applied
+	for _, invalid := range []int32{0, -1} {
+		invalid := invalid
+		update = &UpdateCollection{TenantID: "tenant", DatabaseName: "database", Dimension: &invalid}
+		err = update.Validate(&Collection{TenantID: "tenant", DatabaseName: "database"})
+		var dimensionErr *InvalidDimensionError
+		assert.ErrorAs(t, err, &dimensionErr)
+		assert.Equal(t, invalid, dimensionErr.Dimension)
+		assert.ErrorIs(t, update.Validate(&Collection{TenantID: "tenant", DatabaseName: "database", Dimension: &dimension}), common.ErrInvalidDimension)
+		assert.False(t, errors.As(update.Validate(&Collection{TenantID: "tenant", DatabaseName: "database", Dimension: &dimension}), &mismatch))
+		applied, err := update.Apply(&Collection{TenantID: "tenant", DatabaseName: "database"})
+		assert.Nil(t, applied)
+		assert.ErrorAs(t, err, &dimensionErr)
+	}
+}
+
+func TestApplyMetadataUpdate(t *testing.T) {
+	existingMetadata := NewCollectionMetadata[CollectionMetadataValueType]()
+	existingMetadata.Add("keep", &CollectionMetadataValueStringType{Value: "kept"})
+	existingMetadata.Add("overwrite", &CollectionMetadataValueInt64Type{Value: 1})
+	existingMetadata.Add("remove", &CollectionMetadataValueBoolType{Value: true})
+	existing := &Collection{Metadata: existingMetadata}
+
+	updateMetadata := NewCollectionMetadata[CollectionMetadataValueType]()
+	updateMetadata.Add("overwrite", &CollectionMetadataValueInt64Type{Value: 2})
+	updateMetadata.Add("remove", &CollectionMetadataValueDeleteType{})
+	updateMetadata.Add("new", &CollectionMetadataValueFloat64Type{Value: 1.5})
+
+	// Test case 1: reset with nil metadata clears the metadata
+	result := ApplyMetadataUpdate(existing, &UpdateCollection{ResetMetadata: true})
+	assert.Nil(t, result)
+
+	// Test case 2: reset replaces the metadata with the update's metadata
+	replacement := NewCollectionMetadata[CollectionMetadataValueType]()
+	replacement.Add("only", &CollectionMetadataValueStringType{Value: "value"})
+	result = ApplyMetadataUpdate(existing, &UpdateCollection{ResetMetadata: true, Metadata: replacement})
+	assert.True(t, result.Equals(replacement))
+
+	// Test case 3: partial merge preserves keys not mentioned in the update
+	result = ApplyMetadataUpdate(existing, &UpdateCollection{Metadata: updateMetadata})
+	expected := NewCollectionMetadata[CollectionMetadataValueType]()
+	expected.Add("keep", &CollectionMetadataValueStringType{Value: "kept"})
+	expected.Add("overwrite", &CollectionMetadataValueInt64Type{Value: 2})
+	expected.Add("new", &CollectionMetadataValueFloat64Type{Value: 1.5})
+	assert.True(t, result.Equals(expected))
+
+	// Test case 4: the delete sentinel removes the key
+	_, ok := result.Metadata["remove"]
+	assert.False(t, ok)
+
+	// Test case 5: the existing metadata is not modified
+	assert.Len(t, existingMetadata.Metadata, 3)
+	assert.True(t, existingMetadata.Get("overwrite").Equals(&CollectionMetadataValueInt64Type{Value: 1}))
+
+	// Test case 6: merging without update metadata keeps the existing metadata
+	result = ApplyMetadataUpdate(existing, &UpdateCollection{})
+	assert.True(t, result.Equals(existingMetadata))
+
+	// Test case 7: deleting every key yields nil metadata
+	deleteAll := NewCollectionMetadata[CollectionMetadataValueType]()
+	for key := range existingMetadata.Metadata {
+		deleteAll.Add(key, &CollectionMetadataValueDeleteType{})
+	}
+	result = ApplyMetadataUpdate(existing, &UpdateCollection{Metadata: deleteAll})
+	assert.Nil(t, result)
+
+	// Test case 8: nil existing collection merges onto empty metadata
+	result = ApplyMetadataUpdate(nil, &UpdateCollection{Metadata: replacement})
+	assert.True(t, result.Equals(replacement))
+}
+
+func TestCollectionClone(t *testing.T) {
+	// Test case 1: nil collection
+	var nilCollection *Collection
+	assert.Nil(t, nilCollection.Clone())
+
+	// Test case 2: mutating the clone does not affect the original
+	dimension := int32(128)
+	deletedAt := types.Timestamp(10)
+	metadata := NewCollectionMetadata[CollectionMetadataValueType]()
+	metadata.Add("key", &CollectionMetadataValueStringType{Value: "value"})
+	metadata.Add("count", &CollectionMetadataValueInt64Type{Value: 1})
+	original := &Collection{
+		ID:           types.NewUniqueID(),
+		Name:         "collection",
+		Dimension:    &dimension,
+		Metadata:     metadata,
+		Labels:       map[string]string{"env": "prod"},
+		TenantID:     "tenant",
+		DatabaseName: "database",
+		Ts:           5,
+		LogPosition:  3,
+		Version:      2,
+		DeletedAt:    &deletedAt,
+	}
+	clone := original.Clone()
+	assert.Equal(t, original, clone)
+
+	*clone.Dimension = 256
+	*clone.DeletedAt = 20
+	clone.Name = "renamed"
+	clone.Metadata.Add("new", &CollectionMetadataValueBoolType{Value: true})
+	clone.Metadata.Remove("key")
+	clone.Metadata.Get("count").(*CollectionMetadataValueInt64Type).Value = 2
+	clone.Labels["env"] = "dev"
+
+	assert.Equal(t, int32(128), *original.Dimension)
+	assert.Equal(t, types.Timestamp(10), *original.DeletedAt)
+	assert.Equal(t, "collection", original.Name)
+	assert.Equal(t, "prod", original.Labels["env"])
+	assert.Len(t, original.Metadata.Metadata, 2)
+	value, ok := original.Metadata.GetString("key")
+	assert.True(t, ok)
+	assert.Equal(t, "value", value)
+	count, ok := original.Metadata.GetInt("count")
+	assert.True(t, ok)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestCreateCollectionValidate(t *testing.T) {
+	dimension := int32(3)
+	zero := int32(0)
+	negative := int32(-1)
+	valid := func() *CreateCollection {
+		return &CreateCollection{
+			ID:           types.NewUniqueID(),
+			Name:         "collection",
+			Dimension:    &dimension,
+			TenantID:     "tenant",
+			DatabaseName: "database",
+		}
+	}
+
+	tests := []struct {
+		name     string
+		mutate   func(c *CreateCollection)
+		expected []error
+	}{
+		{name: "valid", mutate: func(c *CreateCollection) {}},
+		{name: "nil dimension", mutate: func(c *CreateCollection) { c.Dimension = nil }},
+		{name: "nil id", mutate: func(c *CreateCollection) { c.ID = types.NilUniqueID() }, expected: []error{common.ErrMissingCollectionID}},
+		{name: "empty name", mutate: func(c *CreateCollection) { c.Name = "" }, expected: []error{common.ErrCollectionNameEmpty}},
+		{name: "empty tenant", mutate: func(c *CreateCollection) { c.TenantID = "" }, expected: []error{common.ErrTenantIDEmpty}},
+		{name: "empty database", mutate: func(c *CreateCollection) { c.DatabaseName = "" }, expected: []error{common.ErrDatabaseNameEmpty}},
+		{
+			name: "all missing",
+			mutate: func(c *CreateCollection) {
+				*c = CreateCollection{}
+			},
+			expected: []error{common.ErrMissingCollectionID, common.ErrCollectionNameEmpty, common.ErrTenantIDEmpty, common.ErrDatabaseNameEmpty},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := valid()
+			tt.mutate(c)
+			err := c.Validate()
+			if len(tt.expected) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			var validationErr *ValidationError
+			assert.ErrorAs(t, err, &validationErr)
+			assert.Len(t, validationErr.Violations, len(tt.expected))
+			for _, expected := range tt.expected {
+				assert.ErrorIs(t, err, expected)
+			}
+		})
+	}
+
+	c := valid()
+	c.Name = "a"
+	var nameErr *InvalidNameError
+	assert.ErrorAs(t, c.Validate(), &nameErr)
+
+	for _, d := range []*int32{&zero, &negative} {
+		c := valid()
+		c.Dimension = d
+		var dimensionErr *InvalidDimensionError
+		assert.ErrorAs(t, c.Validate(), &dimensionErr)
+		assert.Equal(t, *d, dimensionErr.Dimension)
+	}
+}
+
+func TestNewCreateCollectionResult(t *testing.T) {
+	// Test case 1: zero value reports nothing created
+	var zero CreateCollectionResult
+	assert.Nil(t, zero.Collection)
+	assert.False(t, zero.Created)
+
+	// Test case 2: freshly created collection
+	collection := &Collection{ID: types.NewUniqueID(), Name: "collection"}
+	result := NewCreateCollectionResult(collection, true)
+	assert.Same(t, collection, result.Collection)
+	assert.True(t, result.Created)
+
+	// Test case 3: existing collection matched by GetOrCreate
+	result = NewCreateCollectionResult(collection, false)
+	assert.Same(t, collection, result.Collection)
+	assert.False(t, result.Created)
+}
+
+func TestUpdateCollectionCheckVersion(t *testing.T) {
+	existing := &Collection{ID: types.NewUniqueID(), Version: 7, UpdateVersion: 3}
+
+	// Test case 1: nil expected version skips the check
+	update := &UpdateCollection{ID: existing.ID}
+	assert.NoError(t, update.CheckVersion(existing))
+
+	// Test case 2: matching version
+	update.ExpectedVersion = int32Ptr(3)
+	assert.NoError(t, update.CheckVersion(existing))
+
+	// Test case 3: mismatching version, the compaction version is not used
+	update.ExpectedVersion = int32Ptr(7)
+	err := update.CheckVersion(existing)
+	var conflict *VersionConflictError
+	assert.ErrorAs(t, err, &conflict)
+	assert.Equal(t, int32(7), conflict.Expected)
+	assert.Equal(t, int32(3), conflict.Actual)
+
+	// Test case 4: a missing collection is not found rather than a panic
+	assert.ErrorIs(t, update.CheckVersion(nil), common.ErrCollectionNotFound)
+	assert.NoError(t, (&UpdateCollection{ID: existing.ID}).CheckVersion(nil))
+}
+
+func TestCollectionExpiry(t *testing.T) {
+	now := types.Timestamp(100)
+	past := types.Timestamp(50)
+	future := types.Timestamp(150)
+
+	// Test case 1: nil expiry never expires
+	assert.False(t, (&Collection{}).IsExpired(types.MaxTimestamp))
+
+	// Test case 2: future expiry
+	assert.False(t, (&Collection{ExpiresAt: &future}).IsExpired(now))
+
+	// Test case 3: past and exactly reached expiry
+	assert.True(t, (&Collection{ExpiresAt: &past}).IsExpired(now))
+	assert.True(t, (&Collection{ExpiresAt: &now}).IsExpired(now))
+
+	// Test case 4: expiry before creation is rejected on create
+	create := newTestCreateCollection("collection", "tenant", "database")
+	create.Ts = now
+	create.ExpiresAt = &past
+	var expiryErr *InvalidExpiryError
+	assert.ErrorAs(t, create.Validate(), &expiryErr)
+	assert.Equal(t, past, expiryErr.ExpiresAt)
+	assert.Equal(t, now, expiryErr.Ts)
+	create.ExpiresAt = &future
+	assert.NoError(t, create.Validate())
+
+	// Test case 5: clone copies the expiry
+	collection := &Collection{ExpiresAt: &future}
+	clone := collection.Clone()
+	*clone.ExpiresAt = 0
+	assert.Equal(t, future, *collection.ExpiresAt)
+}
+
+func TestFilterCollectionByScope(t *testing.T) {
+	tenant := "tenant"
+	otherTenant := "other_tenant"
+	database := "database"
+	otherDatabase := "other_database"
+	collection := &Collection{ID: types.NewUniqueID(), TenantID: tenant, DatabaseName: database}
+
+	// Test case 1: nil values skip the check
+	assert.True(t, FilterCollectionByScope(collection, nil, nil))
+
+	// Test case 2: tenant only
+	assert.True(t, FilterCollectionByScope(collection, &tenant, nil))
+	assert.False(t, FilterCollectionByScope(collection, &otherTenant, nil))
+
+	// Test case 3: database only
+	assert.True(t, FilterCollectionByScope(collection, nil, &database))
+	assert.False(t, FilterCollectionByScope(collection, nil, &otherDatabase))
+
+	// Test case 4: both
+	assert.True(t, FilterCollectionByScope(collection, &tenant, &database))
+	assert.False(t, FilterCollectionByScope(collection, &tenant, &otherDatabase))
+	assert.False(t, FilterCollectionByScope(collection, &otherTenant, &database))
+}
+
+func TestCollectionEqual(t *testing.T) {
+	newCollection := func() *Collection {
+		dimension := int32(128)
+		metadata := NewCollectionMetadata[CollectionMetadataValueType]()
+		metadata.Add("a", &CollectionMetadataValueStringType{Value: "1"})
+		metadata.Add("b", &CollectionMetadataValueInt64Type{Value: 2})
+		return &Collection{
+			ID:            types.MustParse("00000000-0000-0000-0000-000000000001"),
+			Name:          "collection",
+			Configuration: &CollectionConfiguration{HnswM: int32Ptr(16)},
+			Dimension:     &dimension,
+			Metadata:      metadata,
+			TenantID:      "tenant",
+			DatabaseName:  "database",
+			Ts:            5,
+			LogPosition:   3,
+			Version:       2,
+		}
+	}
+
+	// Test case 1: nil handling
+	var nilCollection *Collection
+	assert.True(t, nilCollection.Equal(nil))
+	assert.False(t, nilCollection.Equal(newCollection()))
+	assert.False(t, newCollection().Equal(nil))
+
+	// Test case 2: identical collections with distinct pointers
+	assert.True(t, newCollection().Equal(newCollection()))
+
+	// Test case 3: metadata inserted in a different order
+	reordered := newCollection()
+	reordered.Metadata = NewCollectionMetadata[CollectionMetadataValueType]()
+	reordered.Metadata.Add("b", &CollectionMetadataValueInt64Type{Value: 2})
+	reordered.Metadata.Add("a", &CollectionMetadataValueStringType{Value: "1"})
+	assert.True(t, newCollection().Equal(reordered))
+
+	// Test case 4: a single differing field
+	tests := []struct {
+		name   string
+		mutate func(c *Collection)
+	}{
+		{"id", func(c *Collection) { c.ID = types.NewUniqueID() }},
+		{"embedding function", func(c *Collection) { c.EmbeddingFunction = stringPtr("openai-ada-002") }},
+		{"name", func(c *Collection) { c.Name = "other" }},
+		{"configuration", func(c *Collection) { c.Configuration.HnswM = int32Ptr(32) }},
+		{"nil dimension", func(c *Collection) { c.Dimension = nil }},
+		{"dimension", func(c *Collection) { *c.Dimension = 256 }},
+		{"metadata type", func(c *Collection) {
+			c.Metadata.Add("b", &CollectionMetadataValueStringType{Value: "2"})
+		}},
+		{"metadata key", func(c *Collection) { c.Metadata.Remove("a") }},
+		{"labels", func(c *Collection) { c.Labels = map[string]string{"env": "prod"} }},
+		{"tenant", func(c *Collection) { c.TenantID = "other" }},
+		{"log position", func(c *Collection) { c.LogPosition = 4 }},
+		{"last compaction time", func(c *Collection) { c.LastCompactionTime = 1 }},
+		{"deleted at", func(c *Collection) {
+			deletedAt := types.Timestamp(10)
+			c.DeletedAt = &deletedAt
+		}},
+		{"state", func(c *Collection) { c.State = CollectionStateDeleting }},
+		{"read only", func(c *Collection) { c.ReadOnly = true }},
+		{"stats", func(c *Collection) { c.Stats = &CollectionStats{RecordCount: 1} }},
+		{"updated by", func(c *Collection) { c.UpdatedBy = "someone" }},
+		{"requires reindex", func(c *Collection) { c.RequiresReindex = true }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			other := newCollection()
+			tt.mutate(other)
+			assert.False(t, newCollection().Equal(other))
+			assert.False(t, other.Equal(newCollection()))
+		})
+	}
+}
+
+func TestMoveCollectionApply(t *testing.T) {
+	id := types.NewUniqueID()
+	newMove := func() *MoveCollection {
+		return &MoveCollection{ID: id, TenantID: "tenant", FromDatabase: "source", ToDatabase: "target"}
+	}
+
+	// Test case 1: successful move
+	collection := &Collection{ID: id, TenantID: "tenant", DatabaseName: "source"}
+	assert.NoError(t, newMove().Apply(collection))
+	assert.Equal(t, "target", collection.DatabaseName)
+
+	// Test case 2: collection is not in the source database
+	collection = &Collection{ID: id, TenantID: "tenant", DatabaseName: "elsewhere"}
+	err := newMove().Apply(collection)
+	var mismatch *DatabaseMismatchError
+	assert.ErrorAs(t, err, &mismatch)
+	assert.Equal(t, "source", mismatch.Expected)
+	assert.Equal(t, "elsewhere", mismatch.Actual)
+	assert.ErrorIs(t, err, common.ErrDatabaseMismatch)
+	assert.Equal(t, "elsewhere", collection.DatabaseName)
+
+	// Test case 3: same source and target
+	move := newMove()
+	move.ToDatabase = "source"
+	collection = &Collection{ID: id, TenantID: "tenant", DatabaseName: "source"}
+	assert.ErrorIs(t, move.Apply(collection), common.ErrSameDatabase)
+	assert.Equal(t, "source", collection.DatabaseName)
+
+	// Test case 4: empty database names
+	move = newMove()
+	move.FromDatabase = ""
+	assert.ErrorIs(t, move.Validate(), common.ErrDatabaseNameEmpty)
+
+	// Test case 5: collection is not the one named by the move
+	other := types.NewUniqueID()
+	collection = &Collection{ID: other, TenantID: "tenant", DatabaseName: "source"}
+	err = newMove().Apply(collection)
+	var idMismatch *CollectionIDMismatchError
+	assert.ErrorAs(t, err, &idMismatch)
+	assert.Equal(t, &CollectionIDMismatchError{Expected: id, Actual: other}, idMismatch)
+	assert.ErrorIs(t, err, common.ErrCollectionNotFound)
+	assert.Equal(t, "source", collection.DatabaseName)
+	assert.ErrorIs(t, newMove().Apply(nil), common.ErrCollectionNotFound)
+
+	// Test case 6: collection is in another tenant
+	collection = &Collection{ID: id, TenantID: "t2", DatabaseName: "source"}
+	err = newMove().Apply(collection)
+	var tenantMismatch *TenantMismatchError
+	assert.ErrorAs(t, err, &tenantMismatch)
+	assert.Equal(t, "tenant", tenantMismatch.Expected)
+	assert.Equal(t, "t2", tenantMismatch.Actual)
+	assert.ErrorIs(t, err, common.ErrTenantMismatch)
+	assert.Equal(t, "source", collection.DatabaseName)
+}
+
+func TestForkCollectionApply(t *testing.T) {
+	metadata := NewCollectionMetadata[CollectionMetadataValueType]()
+	metadata.Add("key", &CollectionMetadataValueStringType{Value: "value"})
+	source := &Collection{
+		ID:            types.NewUniqueID(),
+		Name:          "source",
+		Configuration: &CollectionConfiguration{HnswM: int32Ptr(16)},
+		Dimension:     int32Ptr(128),
+		Metadata:      metadata,
+		TenantID:      "tenant",
+		DatabaseName:  "database",
+		Ts:            1,
+		LogPosition:   42,
+		Version:       3,
+	}
+	newFork := func(name string) *ForkCollection {
+		return &ForkCollection{
+			SourceID:     source.ID,
+			NewID:        types.NewUniqueID(),
+			NewName:      name,
+			TenantID:     "tenant",
+			DatabaseName: "database",
+			Ts:           10,
+		}
+	}
+
+	// Test case 1: successful fork
+	fork := newFork("forked")
+	forked, err := fork.Apply(source)
+	assert.NoError(t, err)
+	assert.Equal(t, fork.NewID, forked.ID)
+	assert.Equal(t, "forked", forked.Name)
+	assert.Equal(t, int32(128), *forked.Dimension)
+	assert.True(t, forked.Configuration.Equal(source.Configuration))
+	assert.True(t, forked.Metadata.Equals(source.Metadata))
+	assert.Equal(t, source.ID, *forked.SourceCollectionID)
+	assert.Equal(t, types.Timestamp(10), *forked.ForkedAt)
+	assert.Equal(t, types.Timestamp(10), forked.Ts)
+	assert.Equal(t, int64(0), forked.LogPosition)
+	assert.Equal(t, int32(0), forked.Version)
+
+	// The fork does not share state with its source.
+	*forked.Dimension = 256
+	forked.Metadata.Add("new", &CollectionMetadataValueBoolType{Value: true})
+	assert.Equal(t, int32(128), *source.Dimension)
+	assert.Len(t, source.Metadata.Metadata, 1)
+
+	// Test case 2: same name in the same database
+	_, err = newFork("source").Apply(source)
+	assert.ErrorIs(t, err, common.ErrCollectionUniqueConstraintViolation)
+
+	// Test case 3: same name in another database is allowed
+	fork = newFork("source")
+	fork.DatabaseName = "other"
+	_, err = fork.Apply(source)
+	assert.NoError(t, err)
+
+	// Test case 4: invalid new name
+	_, err = newFork("_bad").Apply(source)
+	assert.ErrorIs(t, err, common.ErrInvalidName)
+
+	// Test case 5: source does not match
+	fork = newFork("forked")
+	fork.SourceID = types.NewUniqueID()
+	_, err = fork.Apply(source)
+	assert.ErrorIs(t, err, common.ErrCollectionNotFound)
+
+	// Test case 6: the source's idempotency key is not inherited
+	key := "request-1"
+	create := &CreateCollection{ID: types.NewUniqueID(), Name: "keyed", TenantID: "tenant", DatabaseName: "database", Metadata: metadata, IdempotencyKey: &key}
+	keyed := NewCollectionFromCreate(create)
+	fork = newFork("forked")
+	fork.SourceID = keyed.ID
+	forked, err = fork.Apply(keyed)
+	assert.NoError(t, err)
+	assert.False(t, MatchesIdempotencyKey(forked, key))
+	assert.Nil(t, create.IdempotentResult(forked))
+	assert.True(t, forked.Metadata.Equals(metadata))
+	assert.True(t, MatchesIdempotencyKey(keyed, key))
+}
+
+func TestReadOnlyCollection(t *testing.T) {
+	existing := &Collection{TenantID: "tenant", DatabaseName: "database", ID: types.NewUniqueID(), Name: "curated", ReadOnly: true}
+	name := "renamed"
+
+	// Test case 1: update is blocked
+	update := &UpdateCollection{TenantID: "tenant", DatabaseName: "database", ID: existing.ID, Name: &name}
+	err := update.Validate(existing)
+	var readOnly *ReadOnlyError
+	assert.ErrorAs(t, err, &readOnly)
+	assert.Equal(t, existing.ID, readOnly.CollectionID)
+	assert.ErrorIs(t, err, common.ErrReadOnly)
+
+	// Test case 2: delete is blocked
+	deleteCollection := &DeleteCollection{ID: existing.ID, TenantID: "tenant", DatabaseName: "database"}
+	assert.ErrorIs(t, deleteCollection.CheckReadOnly(existing), common.ErrReadOnly)
+
+	// Test case 3: explicit override
+	update.AllowReadOnlyOverride = true
+	assert.NoError(t, update.Validate(existing))
+	deleteCollection.AllowReadOnlyOverride = true
+	assert.NoError(t, deleteCollection.CheckReadOnly(existing))
+
+	// Test case 4: toggling the flag itself is permitted
+	readOnlyValue := false
+	toggle := &UpdateCollection{TenantID: "tenant", DatabaseName: "database", ID: existing.ID, ReadOnly: &readOnlyValue}
+	assert.NoError(t, toggle.Validate(existing))
+	toggle.Name = &name
+	assert.ErrorIs(t, toggle.Validate(existing), common.ErrReadOnly)
+
+	// Test case 5: writable collections are unaffected
+	writable := &Collection{TenantID: "tenant", DatabaseName: "database", ID: types.NewUniqueID(), Name: "writable"}
+	assert.NoError(t, (&UpdateCollection{TenantID: "tenant", DatabaseName: "database", Name: &name}).Validate(writable))
+	assert.NoError(t, (&DeleteCollection{}).CheckReadOnly(writable))
+}
+
+func TestCollectionActors(t *testing.T) {
+	// Test case 1: create records the creator as both actors
+	create := newTestCreateCollection("collection", "tenant", "database")
+	create.CreatedBy = "alice"
+	create.Dimension = int32Ptr(128)
+	collection := NewCollectionFromCreate(create)
+	assert.Equal(t, create.ID, collection.ID)
+	assert.Equal(t, int32(128), *collection.Dimension)
+	assert.Equal(t, "alice", collection.CreatedBy)
+	assert.Equal(t, "alice", collection.UpdatedBy)
+
+	// Test case 2: update preserves CreatedBy and changes UpdatedBy
+	update := &UpdateCollection{TenantID: "tenant", DatabaseName: "database", ID: collection.ID, UpdatedBy: "bob"}
+	assert.NoError(t, update.Validate(collection))
+	ApplyActor(collection, update.UpdatedBy)
+	assert.Equal(t, "alice", collection.CreatedBy)
+	assert.Equal(t, "bob", collection.UpdatedBy)
+
+	// Test case 3: an actor is only required when the policy asks for one
+	create.CreatedBy = ""
+	assert.NoError(t, create.Validate())
+	create.RequireActor = true
+	assert.ErrorIs(t, create.Validate(), common.ErrActorEmpty)
+	update = &UpdateCollection{TenantID: "tenant", DatabaseName: "database", ID: collection.ID, RequireActor: true}
+	assert.ErrorIs(t, update.Validate(collection), common.ErrActorEmpty)
+}
+
+func TestUpdateCollectionReindexOnDimensionChange(t *testing.T) {
+	existing := &Collection{TenantID: "tenant", DatabaseName: "database", ID: types.NewUniqueID(), Name: "collection", Dimension: int32Ptr(128)}
+
+	// Test case 1: without the flag the dimension stays immutable
+	update := &UpdateCollection{TenantID: "tenant", DatabaseName: "database", ID: existing.ID, Dimension: int32Ptr(256)}
+	var mismatch *DimensionMismatchError
+	assert.ErrorAs(t, update.Validate(existing), &mismatch)
+	_, err := update.Apply(existing)
+	assert.ErrorIs(t, err, common.ErrInvalidDimension)
+
+	// Test case 2: the flag permits the change and requires a reindex
+	update.ReindexOnDimensionChange = true
+	assert.NoError(t, update.Validate(existing))
+	updated, err := update.Apply(existing)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(256), *updated.Dimension)
+	assert.True(t, updated.RequiresReindex)
+	assert.Equal(t, int32(128), *existing.Dimension)
+	assert.False(t, existing.RequiresReindex)
+
+	// Test case 3: setting the same or a first dimension needs no reindex
+	update = &UpdateCollection{TenantID: "tenant", DatabaseName: "database", ID: existing.ID, Dimension: int32Ptr(128), ReindexOnDimensionChange: true}
+	updated, err = update.Apply(existing)
+	assert.NoError(t, err)
+	assert.False(t, updated.RequiresReindex)
+	updated, err = (&UpdateCollection{TenantID: "tenant", DatabaseName: "database", Dimension: int32Ptr(64)}).Apply(&Collection{TenantID: "tenant", DatabaseName: "database", Name: "collection"})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(64), *updated.Dimension)
+	assert.False(t, updated.RequiresReindex)
+}
+
+func TestUpdateCollectionApply(t *testing.T) {
+	metadata := NewCollectionMetadata[CollectionMetadataValueType]()
+	metadata.Add("keep", &CollectionMetadataValueStringType{Value: "kept"})
+	existing := &Collection{TenantID: "tenant", DatabaseName: "database", ID: types.NewUniqueID(), Name: "collection", Metadata: metadata, CreatedBy: "alice"}
+	name := " renamed "
+	updateMetadata := NewCollectionMetadata[CollectionMetadataValueType]()
+	updateMetadata.Add("added", &CollectionMetadataValueInt64Type{Value: 1})
+	update := &UpdateCollection{
+		ID:           existing.ID,
+		Name:         &name,
+		Metadata:     updateMetadata,
+		Labels:       map[string]string{"env": "prod"},
+		UpdatedBy:    "bob",
+		TenantID:     "tenant",
+		DatabaseName: "database",
+	}
+	updated, err := update.Apply(existing)
+	assert.NoError(t, err)
+	assert.Equal(t, "renamed", updated.Name)
+	assert.Len(t, updated.Metadata.Metadata, 2)
+	assert.Equal(t, map[string]string{"env": "prod"}, updated.Labels)
+	assert.Equal(t, "alice", updated.CreatedBy)
+	assert.Equal(t, "bob", updated.UpdatedBy)
+	assert.Equal(t, "collection", existing.Name)
+	assert.Len(t, existing.Metadata.Metadata, 1)
+}
+
+func TestCreateCollectionApplyDefaults(t *testing.T) {
+	defaults := map[string]CollectionMetadataValueType{
+		"org_id": &CollectionMetadataValueStringType{Value: "default_org"},
+		"tier":   &CollectionMetadataValueInt64Type{Value: 1},
+	}
+
+	// Test case 1: defaults are injected into nil metadata
+	create := newTestCreateCollection("collection", "tenant", "database")
+	assert.NoError(t, create.ApplyDefaults(defaults))
+	orgID, ok := create.Metadata.GetString("org_id")
+	assert.True(t, ok)
+	assert.Equal(t, "default_org", orgID)
+	tier, ok := create.Metadata.GetInt("tier")
+	assert.True(t, ok)
+	assert.Equal(t, int64(1), tier)
+
+	// Injected values are copies of the defaults.
+	create.Metadata.Get("tier").(*CollectionMetadataValueInt64Type).Value = 2
+	assert.Equal(t, int64(1), defaults["tier"].(*CollectionMetadataValueInt64Type).Value)
+
+	// Test case 2: client-supplied values are not overwritten
+	create = newTestCreateCollection("collection", "tenant", "database")
+	create.Metadata = NewCollectionMetadata[CollectionMetadataValueType]()
+	create.Metadata.Add("org_id", &CollectionMetadataValueStringType{Value: "client_org"})
+	assert.NoError(t, create.ApplyDefaults(defaults))
+	orgID, _ = create.Metadata.GetString("org_id")
+	assert.Equal(t, "client_org", orgID)
+	assert.Len(t, create.Metadata.Metadata, 2)
+
+	// Test case 3: reserved keys in defaults are rejected
+	create = newTestCreateCollection("collection", "tenant", "database")
+	err := create.ApplyDefaults(map[string]CollectionMetadataValueType{
+		ReservedMetadataKeyPrefix + "internal": &CollectionMetadataValueStringType{Value: "x"},
+	})
+	var reserved *ReservedMetadataKeyError
+	assert.ErrorAs(t, err, &reserved)
+	assert.Nil(t, create.Metadata)
+
+	// Test case 4: no defaults leaves nil metadata alone
+	assert.NoError(t, create.ApplyDefaults(nil))
+	assert.Nil(t, create.Metadata)
+}
+
+func TestFilterCollectionNilSafety(t *testing.T) {
+	id := types.NewUniqueID()
+	name := "collection"
+	otherName := "other"
+	tenantID := "tenant"
+	collection := &Collection{ID: id, Name: name, TenantID: tenantID, DatabaseName: "database"}
+
+	// Test case 1: a nil collection never matches and does not panic
+	assert.False(t, FilterCollection(nil, types.NilUniqueID(), nil))
+	assert.False(t, FilterCollection(nil, id, &name, WithIncludeDeleted(), WithReadyOnly()))
+	assert.False(t, FilterCollectionByScope(nil, nil, nil))
+	assert.False(t, FilterCollectionByMetadata(nil, nil))
+	assert.False(t, FilterCollectionByLabels(nil, nil))
+
+	// Test case 2: nil filters do not filter
+	assert.True(t, FilterCollection(collection, types.NilUniqueID(), nil))
+	assert.True(t, FilterCollection(collection, types.NilUniqueID(), nil, WithNameMatch(nil)))
+	assert.True(t, FilterCollectionByScope(collection, nil, nil))
+
+	// Test case 3: mixed matching and non-matching fields
+	tests := []struct {
+		name     string
+		id       types.UniqueID
+		filter   *string
+		expected bool
+	}{
+		{"id and name match", id, &name, true},
+		{"id matches, name does not", id, &otherName, false},
+		{"name matches, id does not", types.NewUniqueID(), &name, false},
+		{"id only", id, nil, true},
+		{"name only", types.NilUniqueID(), &name, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, FilterCollection(collection, tt.id, tt.filter))
+		})
+	}
+
+	// Test case 4: nil entries are skipped when listing
+	result := mustFilterCollections(t, []*Collection{nil, collection, nil}, CollectionListOptions{})
+	assert.Equal(t, []*Collection{collection}, result)
+}
+
+func TestCreateCollectionIdempotencyKey(t *testing.T) {
+	create := newTestCreateCollection("collection", "tenant", "database")
+	create.IdempotencyKey = stringPtr("request-1")
+
+	// Test case 1: the first create stamps the key
+	collection := NewCollectionFromCreate(create)
+	key, ok := collection.Metadata.GetString(IdempotencyKeyMetadataKey)
+	assert.True(t, ok)
+	assert.Equal(t, "request-1", key)
+	assert.True(t, MatchesIdempotencyKey(collection, "request-1"))
+	assert.Nil(t, create.Metadata)
+	assert.NoError(t, create.Validate())
+
+	// Test case 2: a retry with the same key returns the existing collection
+	retry := newTestCreateCollection("collection", "tenant", "database")
+	retry.IdempotencyKey = stringPtr("request-1")
+	result := retry.IdempotentResult(collection)
+	if assert.NotNil(t, result) {
+		assert.Same(t, collection, result.Collection)
+		assert.False(t, result.Created)
+	}
+
+	// Test case 3: a different key or scope is not a retry
+	assert.False(t, MatchesIdempotencyKey(collection, "request-2"))
+	assert.False(t, MatchesIdempotencyKey(collection, ""))
+	assert.False(t, MatchesIdempotencyKey(nil, "request-1"))
+	retry.IdempotencyKey = stringPtr("request-2")
+	assert.Nil(t, retry.IdempotentResult(collection))
+	other := newTestCreateCollection("collection", "tenant", "other")
+	other.IdempotencyKey = stringPtr("request-1")
+	assert.Nil(t, other.IdempotentResult(collection))
+
+	// Test case 4: creates without a key stamp nothing and never match
+	plain := newTestCreateCollection("collection", "tenant", "database")
+	unstamped := NewCollectionFromCreate(plain)
+	assert.Nil(t, unstamped.Metadata)
+	assert.False(t, MatchesIdempotencyKey(unstamped, "request-1"))
+	assert.Nil(t, plain.IdempotentResult(collection))
+}
+
+func TestValidateScope(t *testing.T) {
+	tests := []struct {
+		name         string
+		tenantID     string
+		databaseName string
+		missing      []string
+	}{
+		{name: "valid", tenantID: "tenant", databaseName: "database"},
+		{name: "empty tenant", tenantID: "", databaseName: "database", missing: []string{"tenant_id"}},
+		{name: "empty database", tenantID: "tenant", databaseName: "", missing: []string{"database_name"}},
+		{name: "whitespace only", tenantID: " \t", databaseName: "\n", missing: []string{"tenant_id", "database_name"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			create := newTestCreateCollection("collection", tt.tenantID, tt.databaseName)
+			update := &UpdateCollection{ID: create.ID, TenantID: tt.tenantID, DatabaseName: tt.databaseName}
+			remove := &DeleteCollection{ID: create.ID, TenantID: tt.tenantID, DatabaseName: tt.databaseName}
+			for _, err := range []error{
+				validateScope(tt.tenantID, tt.databaseName),
+				create.Validate(),
+				update.Validate(nil),
+				remove.Validate(),
+			} {
+				if len(tt.missing) == 0 {
+					assert.NoError(t, err)
+					continue
+				}
+				var validationErr *ValidationError
+				assert.ErrorAs(t, err, &validationErr)
+				var fields []string
+				for _, violation := range validationErr.Violations {
+					if scopeErr, ok := violation.(*MissingScopeError); ok {
+						fields = append(fields, scopeErr.Field)
+					}
+				}
+				assert.Equal(t, tt.missing, fields)
+			}
+		})
+	}
+
+	// Test case 1: scope errors unwrap to the existing sentinels
+	err := validateScope("", "")
+	assert.ErrorIs(t, err, common.ErrTenantIDEmpty)
+	assert.ErrorIs(t, err, common.ErrDatabaseNameEmpty)
+	assert.EqualError(t, err, "validation failed: tenant_id must not be empty; database_name must not be empty")
+}
+
+func TestCollectionDimensionHelpers(t *testing.T) {
+	tests := []struct {
+		name      string
+		dimension *int32
+		has       bool
+		value     int32
+		valid     bool
+	}{
+		{name: "nil", dimension: nil, has: false, value: 0, valid: true},
+		{name: "explicit zero", dimension: int32Ptr(0), has: false, value: 0, valid: false},
+		{name: "negative", dimension: int32Ptr(-1), has: false, value: 0, valid: false},
+		{name: "positive", dimension: int32Ptr(128), has: true, value: 128, valid: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collection := &Collection{Dimension: tt.dimension}
+			assert.Equal(t, tt.has, collection.HasDimension())
+			value, ok := collection.DimensionValue()
+			assert.Equal(t, tt.has, ok)
+			assert.Equal(t, tt.value, value)
+
+			create := newTestCreateCollection("collection", "tenant", "database")
+			create.Dimension = tt.dimension
+			err := create.Validate()
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, common.ErrInvalidDimension)
+			}
+		})
+	}
+}
+
+func TestUpdateCollectionEffectiveMetadata(t *testing.T) {
+	existingMetadata := NewCollectionMetadata[CollectionMetadataValueType]()
+	existingMetadata.Add("keep", &CollectionMetadataValueStringType{Value: "kept"})
+	existingMetadata.Add("change", &CollectionMetadataValueInt64Type{Value: 1})
+	existing := &Collection{Metadata: existingMetadata}
+
+	// Test case 1: reset with nil metadata clears it
+	update := &UpdateCollection{ResetMetadata: true}
+	assert.Nil(t, update.EffectiveMetadata(existing))
+
+	// Test case 2: reset with metadata replaces it
+	replacement := NewCollectionMetadata[CollectionMetadataValueType]()
+	replacement.Add("new", &CollectionMetadataValueBoolType{Value: true})
+	update = &UpdateCollection{ResetMetadata: true, Metadata: replacement}
+	assert.True(t, update.EffectiveMetadata(existing).Equals(replacement))
+
+	// Test case 3: without reset the update is merged over the existing keys
+	updateMetadata := NewCollectionMetadata[CollectionMetadataValueType]()
+	updateMetadata.Add("change", &CollectionMetadataValueInt64Type{Value: 2})
+	updateMetadata.Add("added", &CollectionMetadataValueStringType{Value: "added"})
+	update = &UpdateCollection{Metadata: updateMetadata}
+	effective := update.EffectiveMetadata(existing)
+	expected := NewCollectionMetadata[CollectionMetadataValueType]()
+	expected.Add("keep", &CollectionMetadataValueStringType{Value: "kept"})
+	expected.Add("change", &CollectionMetadataValueInt64Type{Value: 2})
+	expected.Add("added", &CollectionMetadataValueStringType{Value: "added"})
+	assert.True(t, effective.Equals(expected))
+
+	// Test case 4: nothing is mutated, and the result does not alias the inputs
+	effective.Metadata["keep"].(*CollectionMetadataValueStringType).Value = "mutated"
+	effective.Metadata["change"].(*CollectionMetadataValueInt64Type).Value = 3
+	value, _ := existing.Metadata.GetString("keep")
+	assert.Equal(t, "kept", value)
+	changed, _ := updateMetadata.GetInt("change")
+	assert.Equal(t, int64(2), changed)
+	assert.Len(t, existing.Metadata.Metadata, 2)
+}
+
+func TestUpdateCollectionMetadataTypeStability(t *testing.T) {
+	existingMetadata := NewCollectionMetadata[CollectionMetadataValueType]()
+	existingMetadata.Add("count", &CollectionMetadataValueInt64Type{Value: 1})
+	existingMetadata.Add("label", &CollectionMetadataValueStringType{Value: "a"})
+	existing := &Collection{TenantID: "tenant", DatabaseName: "database", ID: types.NewUniqueID(), Metadata: existingMetadata}
+
+	// Test case 1: a type-stable update with a new key is accepted
+	metadata := NewCollectionMetadata[CollectionMetadataValueType]()
+	metadata.Add("count", &CollectionMetadataValueInt64Type{Value: 2})
+	metadata.Add("added", &CollectionMetadataValueBoolType{Value: true})
+	metadata.Add("label", &CollectionMetadataValueDeleteType{})
+	update := &UpdateCollection{ID: existing.ID, TenantID: "tenant", DatabaseName: "database", Metadata: metadata, EnforceMetadataTypeStability: true}
+	assert.NoError(t, update.Validate(existing))
+
+	// Test case 2: a type change is rejected under the flag
+	metadata = NewCollectionMetadata[CollectionMetadataValueType]()
+	metadata.Add("count", &CollectionMetadataValueStringType{Value: "two"})
+	update = &UpdateCollection{ID: existing.ID, TenantID: "tenant", DatabaseName: "database", Metadata: metadata, EnforceMetadataTypeStability: true}
+	err := update.Validate(existing)
+	assert.ErrorIs(t, err, common.ErrMetadataTypeChanged)
+	var typeChange *MetadataTypeChangeError
+	assert.ErrorAs(t, err, &typeChange)
+	assert.Equal(t, &MetadataTypeChangeError{Key: "count", Existing: KindInt, Requested: KindString}, typeChange)
+
+	// Test case 3: the same change is allowed without the flag
+	update.EnforceMetadataTypeStability = false
+	assert.NoError(t, update.Validate(existing))
+}
+
+func TestUpdateCollectionValidateAll(t *testing.T) {
+	existing := &Collection{ID: types.NewUniqueID(), Name: "collection", Dimension: int32Ptr(128), TenantID: "tenant", DatabaseName: "database"}
+	siblings := []*Collection{
+		existing,
+		{ID: types.NewUniqueID(), Name: "taken", TenantID: "tenant", DatabaseName: "database"},
+	}
+	updateMetadata := NewCollectionMetadata[CollectionMetadataValueType]()
+	updateMetadata.Add("key", &CollectionMetadataValueStringType{Value: "value"})
+
+	// Test case 1: a valid rename with a metadata change
+	name := "renamed"
+	update := &UpdateCollection{ID: existing.ID, Name: &name, Metadata: updateMetadata, TenantID: "tenant", DatabaseName: "database"}
+	assert.NoError(t, update.ValidateAll(existing, siblings))
+
+	// Test case 2: renaming to its own name is fine
+	same := "collection"
+	update.Name = &same
+	assert.NoError(t, update.ValidateAll(existing, siblings))
+
+	// Test case 3: every violation is reported together
+	taken := "taken"
+	invalidMetadata := NewCollectionMetadata[CollectionMetadataValueType]()
+	invalidMetadata.Add(ReservedMetadataKeyPrefix+"key", &CollectionMetadataValueStringType{Value: "value"})
+	update = &UpdateCollection{ID: existing.ID, Name: &taken, Dimension: int32Ptr(256), Metadata: invalidMetadata, TenantID: "tenant", DatabaseName: "database"}
+	err := update.ValidateAll(existing, siblings)
+	var validationErr *ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Len(t, validationErr.Violations, 3)
+	assert.ErrorIs(t, err, common.ErrInvalidDimension)
+	var reservedErr *ReservedMetadataKeyError
+	assert.ErrorAs(t, err, &reservedErr)
+	assert.ErrorIs(t, err, common.ErrNameConflict)
+
+	// Test case 4: an unset scope is taken from the existing collection
+	update.TenantID, update.DatabaseName = "", " "
+	err = update.ValidateAll(existing, siblings)
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Len(t, validationErr.Violations, 3)
+	assert.ErrorIs(t, err, common.ErrNameConflict)
+	assert.NotErrorIs(t, err, common.ErrDatabaseNameEmpty)
+
+	// Test case 5: scope violations are reported alongside the rest when
+	// neither the update nor the existing collection has a scope
+	unscoped := &Collection{ID: existing.ID, Name: "collection", Dimension: int32Ptr(128)}
+	err = update.ValidateAll(unscoped, siblings)
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Len(t, validationErr.Violations, 4)
+	assert.ErrorIs(t, err, common.ErrTenantIDEmpty)
+	assert.ErrorIs(t, err, common.ErrDatabaseNameEmpty)
+	assert.NotErrorIs(t, err, common.ErrNameConflict)
+}
+
+func TestUpdateCollectionScopeFromExisting(t *testing.T) {
+	existing := &Collection{ID: types.NewUniqueID(), Name: "collection", TenantID: "tenant", DatabaseName: "database"}
+	name := "renamed"
+
+	// Test case 1: an update without a scope, as built by the grpc service
+	update := &UpdateCollection{ID: existing.ID, Name: &name}
+	assert.NoError(t, update.Validate(existing))
+	updated, err := update.Apply(existing)
+	assert.NoError(t, err)
+	assert.Equal(t, "tenant", updated.TenantID)
+	assert.Equal(t, "database", updated.DatabaseName)
+
+	// Test case 2: a partial scope is completed from existing
+	update = &UpdateCollection{ID: existing.ID, Name: &name, TenantID: "tenant"}
+	assert.NoError(t, update.Validate(existing))
+
+	// Test case 3: without an existing collection the scope is required
+	err = update.Validate(nil)
+	assert.ErrorIs(t, err, common.ErrDatabaseNameEmpty)
+	assert.NotErrorIs(t, err, common.ErrTenantIDEmpty)
+
+	// Test case 4: a scope that differs from existing is rejected
+	update = &UpdateCollection{ID: existing.ID, Name: &name, TenantID: "other_tenant", DatabaseName: "other_database"}
+	err = update.Validate(existing)
+	var tenantErr *TenantMismatchError
+	assert.ErrorAs(t, err, &tenantErr)
+	assert.Equal(t, "other_tenant", tenantErr.Expected)
+	assert.Equal(t, "tenant", tenantErr.Actual)
+	var databaseErr *DatabaseMismatchError
+	assert.ErrorAs(t, err, &databaseErr)
+	assert.Equal(t, "other_database", databaseErr.Expected)
+	assert.Equal(t, "database", databaseErr.Actual)
+	assert.ErrorIs(t, err, common.ErrTenantMismatch)
+	assert.ErrorIs(t, err, common.ErrDatabaseMismatch)
+
+	// Test case 5: a matching scope is accepted
+	update = &UpdateCollection{ID: existing.ID, Name: &name, TenantID: "tenant", DatabaseName: "database"}
+	assert.NoError(t, update.Validate(existing))
+}
+
+func TestCollectionAgeAndIdle(t *testing.T) {
+	collection := &Collection{Ts: 100, LastCompactionTime: 150}
+
+	// Test case 1: normal age and idle time
+	assert.Equal(t, types.Timestamp(100), collection.AgeSince(200))
+	assert.Equal(t, int64(50), collection.IdleSince(200))
+
+	// Test case 2: zero timestamps are never old or idle
+	zero := &Collection{}
+	assert.Equal(t, types.Timestamp(0), zero.AgeSince(200))
+	assert.Equal(t, int64(0), zero.IdleSince(200))
+
+	// Test case 3: clock skew clamps to zero
+	assert.Equal(t, types.Timestamp(0), collection.AgeSince(50))
+	assert.Equal(t, int64(0), collection.IdleSince(120))
+}
+
+func TestCopyCollectionApply(t *testing.T) {
+	metadata := NewCollectionMetadata[CollectionMetadataValueType]()
+	metadata.Add("key", &CollectionMetadataValueStringType{Value: "value"})
+	metadata = SetReservedMetadata(metadata, IdempotencyKeyMetadataKey, &CollectionMetadataValueStringType{Value: "request-1"})
+	source := &Collection{
+		ID:                   types.NewUniqueID(),
+		Name:                 "source",
+		ConfigurationJsonStr: `{"a":1}`,
+		Configuration:        &CollectionConfiguration{HnswM: int32Ptr(16)},
+		Dimension:            int32Ptr(128),
+		Metadata:             metadata,
+		TenantID:             "tenant",
+		DatabaseName:         "database",
+		LogPosition:          10,
+		Version:              3,
+	}
+	valid := func() *CopyCollection {
+		return &CopyCollection{SourceID: source.ID, NewID: types.NewUniqueID(), NewName: " copy ", TenantID: "tenant", DatabaseName: "database", Ts: 5}
+	}
+
+	// Test case 1: metadata excluded
+	request := valid()
+	copied, err := request.Apply(source)
+	assert.NoError(t, err)
+	assert.Equal(t, request.NewID, copied.ID)
+	assert.Equal(t, "copy", copied.Name)
+	assert.Equal(t, int32(128), *copied.Dimension)
+	assert.True(t, source.Configuration.Equal(copied.Configuration))
+	assert.NotSame(t, source.Configuration, copied.Configuration)
+	assert.Equal(t, source.ConfigurationJsonStr, copied.ConfigurationJsonStr)
+	assert.Nil(t, copied.Metadata)
+	assert.Equal(t, int64(0), copied.LogPosition)
+	assert.Equal(t, int32(0), copied.Version)
+	assert.Equal(t, types.Timestamp(5), copied.Ts)
+	assert.Nil(t, copied.SourceCollectionID)
+	assert.Nil(t, copied.ForkedAt)
+
+	// Test case 2: metadata included, without the source's idempotency key
+	request = valid()
+	request.IncludeMetadata = true
+	copied, err = request.Apply(source)
+	assert.NoError(t, err)
+	value, _ := copied.Metadata.GetString("key")
+	assert.Equal(t, "value", value)
+	assert.NotContains(t, copied.Metadata.Metadata, IdempotencyKeyMetadataKey)
+	assert.Contains(t, source.Metadata.Metadata, IdempotencyKeyMetadataKey)
+	assert.Nil(t, copied.SourceCollectionID)
+	_, err = request.Apply(&Collection{ID: source.ID, Name: "source", TenantID: "tenant", DatabaseName: "database"})
+	assert.NoError(t, err)
+
+	// Test case 3: invalid or conflicting names are rejected
+	request = valid()
+	request.NewName = "a"
+	_, err = request.Apply(source)
+	assert.ErrorIs(t, err, common.ErrInvalidName)
+	request.NewName = " source"
+	_, err = request.Apply(source)
+	assert.ErrorIs(t, err, common.ErrNameConflict)
+	request.DatabaseName = "other"
+	_, err = request.Apply(source)
+	assert.NoError(t, err)
+
+	// Test case 4: missing fields and a mismatched source
+	_, err = (&CopyCollection{}).Apply(source)
+	assert.ErrorIs(t, err, common.ErrMissingCollectionID)
+	assert.ErrorIs(t, err, common.ErrCollectionNameEmpty)
+	assert.ErrorIs(t, err, common.ErrTenantIDEmpty)
+	request = valid()
+	request.SourceID = types.NewUniqueID()
+	_, err = request.Apply(source)
+	assert.ErrorIs(t, err, common.ErrCollectionNotFound)
+}
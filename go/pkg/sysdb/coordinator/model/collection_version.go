@@ -0,0 +1,38 @@
+package model
+
+import "github.com/chroma-core/chroma/go/pkg/types"
+
+// CollectionVersionEntry records a single version transition of a
+// collection.
+type CollectionVersionEntry struct {
+	Version     int32
+	LogPosition int64
+	Ts          types.Timestamp
+	Reason      string
+}
+
+// CollectionVersionHistory is the ordered list of version transitions of a
+// collection, oldest first.
+type CollectionVersionHistory struct {
+	CollectionID types.UniqueID
+	Entries      []CollectionVersionEntry
+}
+
+// Append adds entry to the history. Versions must strictly increase; an entry
+// that does not advance past the latest version returns a
+// *VersionMismatchError and leaves the history unchanged.
+func (h *CollectionVersionHistory) Append(entry CollectionVersionEntry) error {
+	if latest, ok := h.Latest(); ok && entry.Version <= latest.Version {
+		return &VersionMismatchError{Current: latest.Version, Requested: entry.Version}
+	}
+	h.Entries = append(h.Entries, entry)
+	return nil
+}
+
+// Latest returns the most recent entry, or false when the history is empty.
+func (h *CollectionVersionHistory) Latest() (CollectionVersionEntry, bool) {
+	if len(h.Entries) == 0 {
+		return CollectionVersionEntry{}, false
+	}
+	return h.Entries[len(h.Entries)-1], true
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/chroma-core/chroma/go/pkg/common"
+	"github.com/chroma-core/chroma/go/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectionVersionHistory(t *testing.T) {
+	history := &CollectionVersionHistory{CollectionID: types.NewUniqueID()}
+
+	// Test case 1: Latest on empty history
+	_, ok := history.Latest()
+	assert.False(t, ok)
+
+	// Test case 2: increasing versions are appended in order
+	assert.NoError(t, history.Append(CollectionVersionEntry{Version: 1, LogPosition: 10, Ts: 1, Reason: "compaction"}))
+	assert.NoError(t, history.Append(CollectionVersionEntry{Version: 3, LogPosition: 20, Ts: 2, Reason: "compaction"}))
+	latest, ok := history.Latest()
+	assert.True(t, ok)
+	assert.Equal(t, int32(3), latest.Version)
+	assert.Equal(t, int64(20), latest.LogPosition)
+
+	// Test case 3: repeated version is rejected
+	err := history.Append(CollectionVersionEntry{Version: 3})
+	var mismatch *VersionMismatchError
+	assert.ErrorAs(t, err, &mismatch)
+	assert.Equal(t, int32(3), mismatch.Current)
+	assert.ErrorIs(t, err, common.ErrCollectionVersionInvalid)
+
+	// Test case 4: older version is rejected
+	err = history.Append(CollectionVersionEntry{Version: 2})
+	assert.ErrorIs(t, err, common.ErrCollectionVersionStale)
+	assert.Len(t, history.Entries, 2)
+	latest, _ = history.Latest()
+	assert.Equal(t, int32(3), latest.Version)
+}
@@ -1,6 +1,10 @@
 package model
 
-import "github.com/chroma-core/chroma/go/pkg/types"
+import (
+	"strings"
+
+	"github.com/chroma-core/chroma/go/pkg/types"
+)
 
 type Database struct {
 	ID     string
@@ -16,9 +20,120 @@ type CreateDatabase struct {
 	Ts     types.Timestamp
 }
 
+// Validate requires a tenant and a database name that follows the collection
+// naming rules.
+func (c *CreateDatabase) Validate() error {
+	return validateDatabaseScope(c.Tenant, c.Name)
+}
+
+// FilterDatabase reports whether database belongs to tenantID and has the
+// given name. An empty tenantID or a nil name means that field is not
+// filtered on. A nil database never matches.
+func FilterDatabase(database *Database, tenantID string, name *string) bool {
+	if database == nil {
+		return false
+	}
+	if tenantID != "" && tenantID != database.Tenant {
+		return false
+	}
+	if name != nil && *name != database.Name {
+		return false
+	}
+	return true
+}
+
 type GetDatabase struct {
 	ID     string
 	Name   string
 	Tenant string
 	Ts     types.Timestamp
 }
+
+// DeleteDatabase removes a database together with every collection in it.
+type DeleteDatabase struct {
+	TenantID     string
+	DatabaseName string
+	Ts           types.Timestamp
+}
+
+func (d *DeleteDatabase) Validate() error {
+	return validateDatabaseScope(d.TenantID, d.DatabaseName)
+}
+
+func validateDatabaseScope(tenantID string, databaseName string) error {
+	var violations []error
+	if err := validateScope(tenantID, databaseName); err != nil {
+		violations = append(violations, err)
+	}
+	if strings.TrimSpace(databaseName) != "" {
+		if _, err := NormalizeAndValidateName(databaseName); err != nil {
+			violations = append(violations, err)
+		}
+	}
+	return newValidationError(violations)
+}
+
+// CollectionsInDatabase returns the collections a cascading DeleteDatabase
+// would remove, including ones that are already soft deleted.
+func CollectionsInDatabase(collections []*Collection, tenantID string, databaseName string) []*Collection {
+	var selected []*Collection
+	for _, collection := range collections {
+		if collection != nil && collection.TenantID == tenantID && collection.DatabaseName == databaseName {
+			selected = append(selected, collection)
+		}
+	}
+	return selected
+}
+
+// DatabaseKey is the key CountCollectionsByDatabase uses for a tenant's
+// database.
+func DatabaseKey(tenantID string, databaseName string) string {
+	return tenantID + "/" + databaseName
+}
+
+// CountCollectionsByDatabase counts the live collections of each database,
+// keyed by DatabaseKey. Soft deleted collections are not counted.
+func CountCollectionsByDatabase(collections []*Collection) map[string]int32 {
+	counts := make(map[string]int32)
+	for _, collection := range collections {
+		if collection == nil || IsDeleted(collection) {
+			continue
+		}
+		counts[DatabaseKey(collection.TenantID, collection.DatabaseName)]++
+	}
+	return counts
+}
+
+// CountCollectionsForTenant counts the live collections across every
+// database of tenantID. Soft deleted collections are not counted.
+func CountCollectionsForTenant(collections []*Collection, tenantID string) int32 {
+	var count int32
+	for _, collection := range collections {
+		if collection != nil && !IsDeleted(collection) && collection.TenantID == tenantID {
+			count++
+		}
+	}
+	return count
+}
+
+// DatabaseQuota limits the number of collections in a database. A
+// MaxCollections of 0 means unlimited.
+type DatabaseQuota struct {
+	TenantID       string
+	DatabaseName   string
+	MaxCollections int32
+}
+
+// CheckCollectionQuota reports whether another collection may be created in a
+// database that currently holds currentCount collections.
+func CheckCollectionQuota(quota DatabaseQuota, currentCount int32) error {
+	if quota.MaxCollections > 0 && currentCount >= quota.MaxCollections {
+		return &QuotaExceededError{
+			TenantID:     quota.TenantID,
+			DatabaseName: quota.DatabaseName,
+			Limit:        quota.MaxCollections,
+			Current:      currentCount,
+		}
+	}
+	return nil
+}
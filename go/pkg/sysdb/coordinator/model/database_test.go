@@ -0,0 +1,147 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/chroma-core/chroma/go/pkg/common"
+	"github.com/chroma-core/chroma/go/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeleteDatabaseValidate(t *testing.T) {
+	// Test case 1: valid request
+	request := &DeleteDatabase{TenantID: "tenant", DatabaseName: "database"}
+	assert.NoError(t, request.Validate())
+
+	// Test case 2: empty tenant
+	request = &DeleteDatabase{DatabaseName: "database"}
+	assert.ErrorIs(t, request.Validate(), common.ErrTenantIDEmpty)
+
+	// Test case 3: empty database, with both violations reported
+	request = &DeleteDatabase{}
+	err := request.Validate()
+	assert.ErrorIs(t, err, common.ErrTenantIDEmpty)
+	assert.ErrorIs(t, err, common.ErrDatabaseNameEmpty)
+}
+
+func TestCreateDatabaseValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		request  *CreateDatabase
+		expected []error
+	}{
+		{name: "valid", request: &CreateDatabase{Name: "database", Tenant: "tenant"}},
+		{name: "empty tenant", request: &CreateDatabase{Name: "database"}, expected: []error{common.ErrTenantIDEmpty}},
+		{name: "whitespace tenant", request: &CreateDatabase{Name: "database", Tenant: " "}, expected: []error{common.ErrTenantIDEmpty}},
+		{name: "empty name", request: &CreateDatabase{Tenant: "tenant"}, expected: []error{common.ErrDatabaseNameEmpty}},
+		{name: "short name", request: &CreateDatabase{Name: "db", Tenant: "tenant"}, expected: []error{common.ErrInvalidName}},
+		{name: "illegal character", request: &CreateDatabase{Name: "data base", Tenant: "tenant"}, expected: []error{common.ErrInvalidName}},
+		{name: "all invalid", request: &CreateDatabase{Name: "-db-"}, expected: []error{common.ErrTenantIDEmpty, common.ErrInvalidName}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.request.Validate()
+			if len(tt.expected) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			var validationErr *ValidationError
+			assert.ErrorAs(t, err, &validationErr)
+			assert.Len(t, validationErr.Violations, len(tt.expected))
+			for _, expected := range tt.expected {
+				assert.ErrorIs(t, err, expected)
+			}
+		})
+	}
+
+	// Test case 1: delete requests follow the same naming rules
+	assert.ErrorIs(t, (&DeleteDatabase{TenantID: "tenant", DatabaseName: "db"}).Validate(), common.ErrInvalidName)
+}
+
+func TestFilterDatabase(t *testing.T) {
+	database := &Database{ID: "id", Name: "database", Tenant: "tenant"}
+	name := "database"
+	other := "other"
+
+	// Test case 1: unfiltered fields match
+	assert.True(t, FilterDatabase(database, "", nil))
+	assert.True(t, FilterDatabase(database, "tenant", nil))
+	assert.True(t, FilterDatabase(database, "tenant", &name))
+
+	// Test case 2: mismatched tenant or name
+	assert.False(t, FilterDatabase(database, "other", &name))
+	assert.False(t, FilterDatabase(database, "tenant", &other))
+
+	// Test case 3: nil database never matches
+	assert.False(t, FilterDatabase(nil, "", nil))
+}
+
+func TestCollectionsInDatabase(t *testing.T) {
+	deletedAt := types.Timestamp(1)
+	collections := []*Collection{
+		{Name: "a", TenantID: "tenant", DatabaseName: "database"},
+		{Name: "b", TenantID: "tenant", DatabaseName: "other"},
+		{Name: "c", TenantID: "other", DatabaseName: "database"},
+		nil,
+		{Name: "d", TenantID: "tenant", DatabaseName: "database", DeletedAt: &deletedAt},
+	}
+
+	// Test case 1: selects only collections in the tenant's database
+	assert.Equal(t, []string{"a", "d"}, collectionNames(CollectionsInDatabase(collections, "tenant", "database")))
+
+	// Test case 2: no matches
+	assert.Empty(t, CollectionsInDatabase(collections, "tenant", "missing"))
+}
+
+func TestCheckCollectionQuota(t *testing.T) {
+	quota := DatabaseQuota{TenantID: "tenant", DatabaseName: "database", MaxCollections: 10}
+
+	// Test case 1: under the limit
+	assert.NoError(t, CheckCollectionQuota(quota, 0))
+	assert.NoError(t, CheckCollectionQuota(quota, 9))
+
+	// Test case 2: at and over the limit
+	err := CheckCollectionQuota(quota, 10)
+	var exceeded *QuotaExceededError
+	assert.ErrorAs(t, err, &exceeded)
+	assert.Equal(t, int32(10), exceeded.Limit)
+	assert.Equal(t, int32(10), exceeded.Current)
+	assert.ErrorIs(t, err, common.ErrQuotaExceeded)
+	assert.ErrorIs(t, CheckCollectionQuota(quota, 11), common.ErrQuotaExceeded)
+
+	// Test case 3: zero means unlimited
+	assert.NoError(t, CheckCollectionQuota(DatabaseQuota{}, 50000))
+}
+
+func TestCountCollections(t *testing.T) {
+	deletedAt := types.Timestamp(1)
+	collections := []*Collection{
+		{Name: "a", TenantID: "tenant", DatabaseName: "database"},
+		{Name: "b", TenantID: "tenant", DatabaseName: "database"},
+		{Name: "c", TenantID: "tenant", DatabaseName: "other"},
+		{Name: "d", TenantID: "other", DatabaseName: "database"},
+		{Name: "e", TenantID: "tenant", DatabaseName: "database", DeletedAt: &deletedAt},
+		{Name: "f", TenantID: "tenant", DatabaseName: "deleted", DeletedAt: &deletedAt},
+		nil,
+	}
+
+	// Test case 1: counts per database, excluding soft deleted collections
+	assert.Equal(t, map[string]int32{
+		"tenant/database": 2,
+		"tenant/other":    1,
+		"other/database":  1,
+	}, CountCollectionsByDatabase(collections))
+	assert.Empty(t, CountCollectionsByDatabase(nil))
+
+	// Test case 2: counts per tenant
+	assert.Equal(t, int32(3), CountCollectionsForTenant(collections, "tenant"))
+	assert.Equal(t, int32(1), CountCollectionsForTenant(collections, "other"))
+	assert.Equal(t, int32(0), CountCollectionsForTenant(collections, "missing"))
+
+	// Test case 3: the counts feed the quota checks
+	counts := CountCollectionsByDatabase(collections)
+	quota := DatabaseQuota{TenantID: "tenant", DatabaseName: "database", MaxCollections: 2}
+	assert.ErrorIs(t, CheckCollectionQuota(quota, counts[DatabaseKey(quota.TenantID, quota.DatabaseName)]), common.ErrQuotaExceeded)
+	usage := TenantUsage{CollectionsTotal: CountCollectionsForTenant(collections, "tenant")}
+	assert.NoError(t, CheckTenantLimits(TenantLimits{MaxCollectionsTotal: 3}, usage))
+}
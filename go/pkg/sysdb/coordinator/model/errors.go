@@ -0,0 +1,506 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/chroma-core/chroma/go/pkg/common"
+	"github.com/chroma-core/chroma/go/pkg/types"
+)
+
+// ValidationError aggregates every violation found while validating a model so
+// they can be reported together. errors.Is and errors.As see each violation.
+type ValidationError struct {
+	Violations []error
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		messages = append(messages, violation.Error())
+	}
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+func (e *ValidationError) Unwrap() []error {
+	return e.Violations
+}
+
+// newValidationError returns nil when there are no violations. Violations
+// that are themselves a *ValidationError are flattened into the result.
+func newValidationError(violations []error) error {
+	if len(violations) == 0 {
+		return nil
+	}
+	flattened := make([]error, 0, len(violations))
+	for _, violation := range violations {
+		if nested, ok := violation.(*ValidationError); ok {
+			flattened = append(flattened, nested.Violations...)
+			continue
+		}
+		flattened = append(flattened, violation)
+	}
+	return &ValidationError{Violations: flattened}
+}
+
+// appendViolation adds violation to the violations already reported by err.
+func appendViolation(err error, violation error) error {
+	if err == nil {
+		return newValidationError([]error{violation})
+	}
+	if validationErr, ok := err.(*ValidationError); ok {
+		violations := make([]error, 0, len(validationErr.Violations)+1)
+		violations = append(violations, validationErr.Violations...)
+		return newValidationError(append(violations, violation))
+	}
+	return newValidationError([]error{err, violation})
+}
+
+type InvalidNameError struct {
+	Name   string
+	Reason string
+}
+
+func (e *InvalidNameError) Error() string {
+	return fmt.Sprintf("invalid name %q: %s", e.Name, e.Reason)
+}
+
+func (e *InvalidNameError) Unwrap() error {
+	return common.ErrInvalidName
+}
+
+type InvalidNamePatternError struct {
+	Pattern string
+	Err     error
+}
+
+func (e *InvalidNamePatternError) Error() string {
+	return fmt.Sprintf("invalid name pattern %q: %v", e.Pattern, e.Err)
+}
+
+func (e *InvalidNamePatternError) Unwrap() error {
+	return e.Err
+}
+
+type InvalidDimensionError struct {
+	Dimension int32
+}
+
+func (e *InvalidDimensionError) Error() string {
+	return fmt.Sprintf("collection dimension must be positive, got %d", e.Dimension)
+}
+
+func (e *InvalidDimensionError) Unwrap() error {
+	return common.ErrInvalidDimension
+}
+
+type InvalidExpiryError struct {
+	ExpiresAt types.Timestamp
+	Ts        types.Timestamp
+}
+
+func (e *InvalidExpiryError) Error() string {
+	return fmt.Sprintf("collection expiry %d is before its creation time %d", e.ExpiresAt, e.Ts)
+}
+
+type InvalidConfigurationError struct {
+	Field  string
+	Reason string
+}
+
+func (e *InvalidConfigurationError) Error() string {
+	return fmt.Sprintf("invalid collection configuration %s: %s", e.Field, e.Reason)
+}
+
+type InvalidDistanceFunctionError struct {
+	DistanceFunction string
+}
+
+func (e *InvalidDistanceFunctionError) Error() string {
+	return fmt.Sprintf("invalid distance function %q, must be one of %s, %s or %s", e.DistanceFunction, SpaceL2, SpaceCosine, SpaceIP)
+}
+
+type InvalidEmbeddingFunctionError struct {
+	EmbeddingFunction string
+	Reason            string
+}
+
+func (e *InvalidEmbeddingFunctionError) Error() string {
+	return fmt.Sprintf("invalid embedding function %q: %s", e.EmbeddingFunction, e.Reason)
+}
+
+type DimensionMismatchError struct {
+	Existing  int32
+	Requested int32
+}
+
+func (e *DimensionMismatchError) Error() string {
+	return fmt.Sprintf("collection dimension cannot be changed from %d to %d", e.Existing, e.Requested)
+}
+
+func (e *DimensionMismatchError) Unwrap() error {
+	return common.ErrInvalidDimension
+}
+
+type LogPositionRegressionError struct {
+	Current   int64
+	Requested int64
+}
+
+func (e *LogPositionRegressionError) Error() string {
+	return fmt.Sprintf("log position cannot move back from %d to %d", e.Current, e.Requested)
+}
+
+func (e *LogPositionRegressionError) Unwrap() error {
+	return common.ErrLogPositionRegression
+}
+
+// Is also matches common.ErrCollectionLogPositionStale, which the DAO returns
+// for the same condition.
+func (e *LogPositionRegressionError) Is(target error) bool {
+	return target == common.ErrCollectionLogPositionStale
+}
+
+type VersionMismatchError struct {
+	Current   int32
+	Requested int32
+}
+
+func (e *VersionMismatchError) Error() string {
+	return fmt.Sprintf("collection version mismatch: current %d, requested %d", e.Current, e.Requested)
+}
+
+func (e *VersionMismatchError) Unwrap() error {
+	if e.Requested < e.Current {
+		return common.ErrCollectionVersionStale
+	}
+	return common.ErrCollectionVersionInvalid
+}
+
+type VersionConflictError struct {
+	Expected int32
+	Actual   int32
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("collection was modified concurrently: expected version %d, actual %d", e.Expected, e.Actual)
+}
+
+func (e *VersionConflictError) Unwrap() error {
+	return common.ErrVersionConflict
+}
+
+// MetadataTooLargeError reports a metadata limit violation. Key is empty when
+// the metadata has too many keys.
+type MetadataTooLargeError struct {
+	Key   string
+	Limit int
+	Size  int
+}
+
+func (e *MetadataTooLargeError) Error() string {
+	if e.Key == "" {
+		return fmt.Sprintf("metadata has %d keys, limit is %d", e.Size, e.Limit)
+	}
+	return fmt.Sprintf("metadata key %q exceeds size limit: %d bytes, limit is %d", e.Key, e.Size, e.Limit)
+}
+
+func (e *MetadataTooLargeError) Unwrap() error {
+	return common.ErrMetadataTooLarge
+}
+
+type ReservedMetadataKeyError struct {
+	Key string
+}
+
+func (e *ReservedMetadataKeyError) Error() string {
+	return fmt.Sprintf("metadata key %q uses the reserved prefix %q", e.Key, ReservedMetadataKeyPrefix)
+}
+
+type DuplicateCollectionNameError struct {
+	Name         string
+	TenantID     string
+	DatabaseName string
+	FirstIndex   int
+}
+
+func (e *DuplicateCollectionNameError) Error() string {
+	return fmt.Sprintf("collection name %q in %s/%s duplicates batch item %d", e.Name, e.TenantID, e.DatabaseName, e.FirstIndex)
+}
+
+func (e *DuplicateCollectionNameError) Unwrap() error {
+	return common.ErrCollectionUniqueConstraintViolation
+}
+
+type UnsupportedMetadataValueError struct {
+	Key   string
+	Value interface{}
+}
+
+func (e *UnsupportedMetadataValueError) Error() string {
+	return fmt.Sprintf("metadata key %q has unsupported value type %T", e.Key, e.Value)
+}
+
+func (e *UnsupportedMetadataValueError) Unwrap() error {
+	return common.ErrUnknownCollectionMetadataType
+}
+
+type MetadataTypeChangeError struct {
+	Key       string
+	Existing  MetadataValueKind
+	Requested MetadataValueKind
+}
+
+func (e *MetadataTypeChangeError) Error() string {
+	return fmt.Sprintf("metadata key %q cannot change type from %s to %s", e.Key, e.Existing, e.Requested)
+}
+
+func (e *MetadataTypeChangeError) Unwrap() error {
+	return common.ErrMetadataTypeChanged
+}
+
+type DatabaseMismatchError struct {
+	CollectionID types.UniqueID
+	Expected     string
+	Actual       string
+}
+
+func (e *DatabaseMismatchError) Error() string {
+	return fmt.Sprintf("collection %s is in database %q, expected %q", e.CollectionID, e.Actual, e.Expected)
+}
+
+func (e *DatabaseMismatchError) Unwrap() error {
+	return common.ErrDatabaseMismatch
+}
+
+type TenantMismatchError struct {
+	CollectionID types.UniqueID
+	Expected     string
+	Actual       string
+}
+
+func (e *TenantMismatchError) Error() string {
+	return fmt.Sprintf("collection %s is in tenant %q, expected %q", e.CollectionID, e.Actual, e.Expected)
+}
+
+func (e *TenantMismatchError) Unwrap() error {
+	return common.ErrTenantMismatch
+}
+
+// CollectionIDMismatchError reports a request applied to a collection other
+// than the one it names.
+type CollectionIDMismatchError struct {
+	Expected types.UniqueID
+	Actual   types.UniqueID
+}
+
+func (e *CollectionIDMismatchError) Error() string {
+	return fmt.Sprintf("request is for collection %s, got collection %s", e.Expected, e.Actual)
+}
+
+func (e *CollectionIDMismatchError) Unwrap() error {
+	return common.ErrCollectionNotFound
+}
+
+type InvalidLabelError struct {
+	Key    string
+	Reason string
+}
+
+func (e *InvalidLabelError) Error() string {
+	return fmt.Sprintf("invalid label %q: %s", e.Key, e.Reason)
+}
+
+func (e *InvalidLabelError) Unwrap() error {
+	return common.ErrInvalidLabel
+}
+
+// InvalidFlushError reports a flush compaction field that is malformed on its
+// own, independent of the collection it is applied to.
+type InvalidFlushError struct {
+	Field  string
+	Reason string
+}
+
+func (e *InvalidFlushError) Error() string {
+	return fmt.Sprintf("invalid flush %s: %s", e.Field, e.Reason)
+}
+
+func (e *InvalidFlushError) Unwrap() error {
+	return common.ErrInvalidFlush
+}
+
+type UnknownSegmentScopeError struct {
+	Scope SegmentScope
+}
+
+func (e *UnknownSegmentScopeError) Error() string {
+	return fmt.Sprintf("unknown segment scope %q", string(e.Scope))
+}
+
+func (e *UnknownSegmentScopeError) Unwrap() error {
+	return common.ErrUnknownSegmentScope
+}
+
+type SegmentCompactionConflictError struct {
+	SegmentID types.UniqueID
+	FileType  string
+}
+
+func (e *SegmentCompactionConflictError) Error() string {
+	return fmt.Sprintf("segment %s has conflicting files for file type %q", e.SegmentID, e.FileType)
+}
+
+func (e *SegmentCompactionConflictError) Unwrap() error {
+	return common.ErrInvalidFlush
+}
+
+type ReadOnlyError struct {
+	CollectionID types.UniqueID
+}
+
+func (e *ReadOnlyError) Error() string {
+	return fmt.Sprintf("collection %s is read only", e.CollectionID)
+}
+
+func (e *ReadOnlyError) Unwrap() error {
+	return common.ErrReadOnly
+}
+
+type QuotaExceededError struct {
+	TenantID     string
+	DatabaseName string
+	Limit        int32
+	Current      int32
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("database %s/%s has %d collections, limit is %d", e.TenantID, e.DatabaseName, e.Current, e.Limit)
+}
+
+func (e *QuotaExceededError) Unwrap() error {
+	return common.ErrQuotaExceeded
+}
+
+type InvalidCollectionIDError struct {
+	ID string
+}
+
+func (e *InvalidCollectionIDError) Error() string {
+	return fmt.Sprintf("invalid collection id %q", e.ID)
+}
+
+func (e *InvalidCollectionIDError) Unwrap() error {
+	return common.ErrCollectionIDFormat
+}
+
+type DatabaseLimitExceededError struct {
+	Limit int32
+	Usage int32
+}
+
+func (e *DatabaseLimitExceededError) Error() string {
+	return fmt.Sprintf("tenant has %d databases, limit is %d", e.Usage, e.Limit)
+}
+
+func (e *DatabaseLimitExceededError) Unwrap() error {
+	return common.ErrQuotaExceeded
+}
+
+type CollectionLimitExceededError struct {
+	Limit int32
+	Usage int32
+}
+
+func (e *CollectionLimitExceededError) Error() string {
+	return fmt.Sprintf("tenant has %d collections, limit is %d", e.Usage, e.Limit)
+}
+
+func (e *CollectionLimitExceededError) Unwrap() error {
+	return common.ErrQuotaExceeded
+}
+
+type MetadataBytesLimitExceededError struct {
+	Limit int32
+	Usage int32
+}
+
+func (e *MetadataBytesLimitExceededError) Error() string {
+	return fmt.Sprintf("collection metadata is %d bytes, tenant limit is %d", e.Usage, e.Limit)
+}
+
+func (e *MetadataBytesLimitExceededError) Unwrap() error {
+	return common.ErrQuotaExceeded
+}
+
+// MissingScopeError reports an empty or whitespace-only scope field. Field is
+// "tenant_id" or "database_name".
+type MissingScopeError struct {
+	Field string
+}
+
+func (e *MissingScopeError) Error() string {
+	return fmt.Sprintf("%s must not be empty", e.Field)
+}
+
+func (e *MissingScopeError) Unwrap() error {
+	if e.Field == scopeFieldTenantID {
+		return common.ErrTenantIDEmpty
+	}
+	return common.ErrDatabaseNameEmpty
+}
+
+type InvalidCompactionTimeError struct {
+	TenantID string
+	Time     int64
+}
+
+func (e *InvalidCompactionTimeError) Error() string {
+	return fmt.Sprintf("tenant %q last compaction time must not be negative, got %d", e.TenantID, e.Time)
+}
+
+func (e *InvalidCompactionTimeError) Unwrap() error {
+	return common.ErrInvalidCompactionTime
+}
+
+// NameConflictError reports that ExistingID already uses Name in the tenant's
+// database.
+type NameConflictError struct {
+	Name         string
+	TenantID     string
+	DatabaseName string
+	ExistingID   types.UniqueID
+}
+
+func (e *NameConflictError) Error() string {
+	return fmt.Sprintf("collection name %q is already used by collection %s in database %q of tenant %q", e.Name, e.ExistingID, e.DatabaseName, e.TenantID)
+}
+
+func (e *NameConflictError) Unwrap() error {
+	return common.ErrNameConflict
+}
+
+type InvalidTimeWindowError struct {
+	After  types.Timestamp
+	Before types.Timestamp
+}
+
+func (e *InvalidTimeWindowError) Error() string {
+	return fmt.Sprintf("created after %d is later than created before %d", e.After, e.Before)
+}
+
+func (e *InvalidTimeWindowError) Unwrap() error {
+	return common.ErrInvalidTimeWindow
+}
+
+type MalformedMetadataJSONError struct {
+	Err error
+}
+
+func (e *MalformedMetadataJSONError) Error() string {
+	return fmt.Sprintf("%s: %v", common.ErrMalformedMetadataJSON, e.Err)
+}
+
+func (e *MalformedMetadataJSONError) Unwrap() error {
+	return common.ErrMalformedMetadataJSON
+}
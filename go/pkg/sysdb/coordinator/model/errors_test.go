@@ -0,0 +1,65 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/chroma-core/chroma/go/pkg/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypedErrorsIs(t *testing.T) {
+	tests := []struct {
+		err      error
+		sentinel error
+	}{
+		{err: &InvalidNameError{Name: "a", Reason: "too short"}, sentinel: common.ErrInvalidName},
+		{err: &InvalidDimensionError{Dimension: 0}, sentinel: common.ErrInvalidDimension},
+		{err: &DimensionMismatchError{Existing: 1, Requested: 2}, sentinel: common.ErrInvalidDimension},
+		{err: &MetadataTooLargeError{Key: "key", Limit: 1, Size: 2}, sentinel: common.ErrMetadataTooLarge},
+		{err: &VersionConflictError{Expected: 1, Actual: 2}, sentinel: common.ErrVersionConflict},
+		{err: &LogPositionRegressionError{Current: 2, Requested: 1}, sentinel: common.ErrLogPositionRegression},
+		{err: &LogPositionRegressionError{Current: 2, Requested: 1}, sentinel: common.ErrCollectionLogPositionStale},
+		{err: &MissingScopeError{Field: scopeFieldTenantID}, sentinel: common.ErrTenantIDEmpty},
+		{err: &MissingScopeError{Field: scopeFieldDatabaseName}, sentinel: common.ErrDatabaseNameEmpty},
+		{err: &TenantMismatchError{Expected: "a", Actual: "b"}, sentinel: common.ErrTenantMismatch},
+		{err: &CollectionIDMismatchError{}, sentinel: common.ErrCollectionNotFound},
+		{err: &MetadataTypeChangeError{Key: "key", Existing: KindInt, Requested: KindString}, sentinel: common.ErrMetadataTypeChanged},
+	}
+	for _, tt := range tests {
+		assert.ErrorIs(t, tt.err, tt.sentinel)
+		assert.ErrorIs(t, fmt.Errorf("wrapped: %w", tt.err), tt.sentinel)
+		assert.ErrorIs(t, newValidationError([]error{common.ErrTenantIDEmpty, tt.err}), tt.sentinel)
+	}
+	assert.NotErrorIs(t, &InvalidNameError{}, common.ErrInvalidDimension)
+}
+
+func TestTypedErrorsAs(t *testing.T) {
+	// Test case 1: typed errors are extracted from wrapped errors
+	err := fmt.Errorf("create collection: %w", &InvalidNameError{Name: "a", Reason: "too short"})
+	var nameErr *InvalidNameError
+	assert.True(t, errors.As(err, &nameErr))
+	assert.Equal(t, "a", nameErr.Name)
+
+	// Test case 2: typed errors are extracted from validation results
+	create := &CreateCollection{Name: "a", Dimension: int32Ptr(-1)}
+	err = fmt.Errorf("create collection: %w", create.Validate())
+	assert.True(t, errors.As(err, &nameErr))
+	var dimensionErr *InvalidDimensionError
+	assert.True(t, errors.As(err, &dimensionErr))
+	assert.Equal(t, int32(-1), dimensionErr.Dimension)
+	var validationErr *ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+
+	// Test case 3: flush and update errors
+	flush := &FlushCollectionCompaction{LogPosition: 1}
+	err = fmt.Errorf("flush: %w", flush.Validate(2, 0))
+	var regressionErr *LogPositionRegressionError
+	assert.True(t, errors.As(err, &regressionErr))
+	update := &UpdateCollection{ExpectedVersion: int32Ptr(1)}
+	err = fmt.Errorf("update: %w", update.CheckVersion(&Collection{UpdateVersion: 2}))
+	var conflictErr *VersionConflictError
+	assert.True(t, errors.As(err, &conflictErr))
+	assert.Equal(t, int32(2), conflictErr.Actual)
+}
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/chroma-core/chroma/go/pkg/types"
+)
+
+const (
+	MinNameLength = 3
+	MaxNameLength = 63
+)
+
+// NormalizeAndValidateName trims surrounding whitespace from name and checks
+// it against the naming rules shared by collections and other named models:
+// 3 to 63 characters from [a-zA-Z0-9._-], starting and ending with an
+// alphanumeric character.
+func NormalizeAndValidateName(name string) (string, error) {
+	normalized := strings.TrimSpace(name)
+	if len(normalized) < MinNameLength || len(normalized) > MaxNameLength {
+		return "", &InvalidNameError{Name: name, Reason: fmt.Sprintf("length must be between %d and %d characters", MinNameLength, MaxNameLength)}
+	}
+	for _, r := range normalized {
+		if !isNameAlphanumeric(r) && r != '.' && r != '_' && r != '-' {
+			return "", &InvalidNameError{Name: name, Reason: fmt.Sprintf("contains illegal character %q", r)}
+		}
+	}
+	if !isNameAlphanumeric(rune(normalized[0])) || !isNameAlphanumeric(rune(normalized[len(normalized)-1])) {
+		return "", &InvalidNameError{Name: name, Reason: "must start and end with an alphanumeric character"}
+	}
+	return normalized, nil
+}
+
+func isNameAlphanumeric(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+}
+
+// CheckNameUnique returns a *NameConflictError when a collection in tenantID
+// and databaseName other than excludeID already uses name. Names are compared
+// after the whitespace trimming of NormalizeAndValidateName, so renaming a
+// collection to its own name is not a conflict. Soft deleted collections do
+// not hold on to their names.
+func CheckNameUnique(existing []*Collection, name, tenantID, databaseName string, excludeID types.UniqueID) error {
+	normalized := strings.TrimSpace(name)
+	for _, collection := range existing {
+		if collection == nil || collection.ID == excludeID || IsDeleted(collection) {
+			continue
+		}
+		if collection.TenantID != tenantID || collection.DatabaseName != databaseName {
+			continue
+		}
+		if strings.TrimSpace(collection.Name) == normalized {
+			return &NameConflictError{Name: normalized, TenantID: tenantID, DatabaseName: databaseName, ExistingID: collection.ID}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package model
+
+import (
+	"regexp"
+	"strings"
+)
+
+type NameMatchMode int
+
+const (
+	NameMatchExact NameMatchMode = iota
+	NameMatchPrefix
+	NameMatchRegex
+)
+
+// NameMatch matches collection names exactly, by prefix or against a regular
+// expression. Build it with NewNameMatch, or call Validate, so an invalid
+// pattern is reported before any filtering happens; FilterCollections and
+// CountMatching validate their options. Matches on a regex NameMatch that
+// was never validated reports no names. Once validated, a NameMatch is only
+// read and may be shared by concurrent filters.
+type NameMatch struct {
+	Mode    NameMatchMode
+	Pattern string
+	regex   *regexp.Regexp
+}
+
+func NewNameMatch(mode NameMatchMode, pattern string) (*NameMatch, error) {
+	m := &NameMatch{Mode: mode, Pattern: pattern}
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Validate compiles a regular expression pattern. It must not run
+// concurrently with Matches on the same NameMatch.
+func (m *NameMatch) Validate() error {
+	if m == nil || m.Mode != NameMatchRegex || m.regex != nil {
+		return nil
+	}
+	regex, err := regexp.Compile(m.Pattern)
+	if err != nil {
+		return &InvalidNamePatternError{Pattern: m.Pattern, Err: err}
+	}
+	m.regex = regex
+	return nil
+}
+
+// Matches reports whether name matches. A nil NameMatch matches every name and
+// a regular expression that is invalid or was not compiled by Validate
+// matches none.
+func (m *NameMatch) Matches(name string) bool {
+	if m == nil {
+		return true
+	}
+	switch m.Mode {
+	case NameMatchPrefix:
+		return strings.HasPrefix(name, m.Pattern)
+	case NameMatchRegex:
+		if m.regex == nil {
+			return false
+		}
+		return m.regex.MatchString(name)
+	default:
+		return name == m.Pattern
+	}
+}
@@ -0,0 +1,94 @@
+package model
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/chroma-core/chroma/go/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNameMatch(t *testing.T) {
+	// Test case 1: exact is the default
+	exact := &NameMatch{Pattern: "user_1"}
+	assert.True(t, exact.Matches("user_1"))
+	assert.False(t, exact.Matches("user_12"))
+
+	// Test case 2: prefix hits and misses
+	prefix, err := NewNameMatch(NameMatchPrefix, "user_")
+	assert.NoError(t, err)
+	assert.True(t, prefix.Matches("user_1"))
+	assert.True(t, prefix.Matches("user_"))
+	assert.False(t, prefix.Matches("admin_user_1"))
+
+	// Test case 3: regex hits and misses
+	regex, err := NewNameMatch(NameMatchRegex, `^user_[0-9]+$`)
+	assert.NoError(t, err)
+	assert.True(t, regex.Matches("user_42"))
+	assert.False(t, regex.Matches("user_abc"))
+
+	// Test case 4: invalid regex is an error up front
+	_, err = NewNameMatch(NameMatchRegex, `user_(`)
+	var patternErr *InvalidNamePatternError
+	assert.ErrorAs(t, err, &patternErr)
+	assert.Equal(t, `user_(`, patternErr.Pattern)
+
+	// Test case 5: invalid regex built by hand is caught by list validation and never panics
+	invalid := &NameMatch{Mode: NameMatchRegex, Pattern: `[`}
+	assert.ErrorAs(t, CollectionListOptions{NameMatch: invalid}.Validate(), &patternErr)
+	assert.False(t, invalid.Matches("["))
+
+	// Test case 6: a regex built by hand matches nothing until validated
+	manual := &NameMatch{Mode: NameMatchRegex, Pattern: `^user_`}
+	assert.False(t, manual.Matches("user_1"))
+	assert.NoError(t, manual.Validate())
+	assert.True(t, manual.Matches("user_1"))
+
+	// Test case 7: nil matches everything
+	var nilMatch *NameMatch
+	assert.True(t, nilMatch.Matches("anything"))
+	assert.NoError(t, CollectionListOptions{}.Validate())
+}
+
+func TestFilterCollectionNameMatch(t *testing.T) {
+	collections := []*Collection{
+		{ID: types.NewUniqueID(), Name: "user_1"},
+		{ID: types.NewUniqueID(), Name: "user_2"},
+		{ID: types.NewUniqueID(), Name: "system"},
+	}
+	prefix, err := NewNameMatch(NameMatchPrefix, "user_")
+	assert.NoError(t, err)
+	assert.True(t, FilterCollection(collections[0], types.NilUniqueID(), nil, WithNameMatch(prefix)))
+	assert.False(t, FilterCollection(collections[2], types.NilUniqueID(), nil, WithNameMatch(prefix)))
+	assert.Equal(t, []string{"user_1", "user_2"}, collectionNames(mustFilterCollections(t, collections, CollectionListOptions{NameMatch: prefix})))
+
+	regex, err := NewNameMatch(NameMatchRegex, `sys`)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"system"}, collectionNames(mustFilterCollections(t, collections, CollectionListOptions{NameMatch: regex})))
+
+	// A regex built by hand is compiled by the filter, and an invalid one is an error.
+	manual := CollectionListOptions{NameMatch: &NameMatch{Mode: NameMatchRegex, Pattern: `^user_`}}
+	assert.Equal(t, []string{"user_1", "user_2"}, collectionNames(mustFilterCollections(t, collections, manual)))
+	assert.Equal(t, 2, mustCountMatching(t, collections, manual))
+	invalid := CollectionListOptions{NameMatch: &NameMatch{Mode: NameMatchRegex, Pattern: `[`}}
+	result, err := FilterCollections(collections, invalid)
+	assert.Nil(t, result)
+	var patternErr *InvalidNamePatternError
+	assert.ErrorAs(t, err, &patternErr)
+	count, err := CountMatching(collections, invalid)
+	assert.Equal(t, 0, count)
+	assert.ErrorAs(t, err, &patternErr)
+
+	// Validated options can be shared by concurrent filters.
+	opts := CollectionListOptions{NameMatch: &NameMatch{Mode: NameMatchRegex, Pattern: `^user_`}}
+	assert.NoError(t, opts.Validate())
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Len(t, mustFilterCollections(t, collections, opts), 2)
+		}()
+	}
+	wg.Wait()
+}
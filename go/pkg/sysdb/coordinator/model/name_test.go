@@ -0,0 +1,77 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/chroma-core/chroma/go/pkg/common"
+	"github.com/chroma-core/chroma/go/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeAndValidateName(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		valid    bool
+	}{
+		{name: "valid", input: "my_collection-1.v2", expected: "my_collection-1.v2", valid: true},
+		{name: "minimum length", input: "abc", expected: "abc", valid: true},
+		{name: "maximum length", input: strings.Repeat("a", 63), expected: strings.Repeat("a", 63), valid: true},
+		{name: "trims whitespace", input: "  collection\t\n", expected: "collection", valid: true},
+		{name: "too short", input: "ab"},
+		{name: "too short after trimming", input: "  ab  "},
+		{name: "too long", input: strings.Repeat("a", 64)},
+		{name: "empty", input: ""},
+		{name: "illegal space", input: "my collection"},
+		{name: "illegal character", input: "collection/1"},
+		{name: "illegal unicode", input: "collé"},
+		{name: "starts with non-alphanumeric", input: "_collection"},
+		{name: "ends with non-alphanumeric", input: "collection."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			normalized, err := NormalizeAndValidateName(tt.input)
+			if tt.valid {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, normalized)
+				return
+			}
+			var nameErr *InvalidNameError
+			assert.ErrorAs(t, err, &nameErr)
+			assert.Equal(t, tt.input, nameErr.Name)
+			assert.Equal(t, "", normalized)
+		})
+	}
+}
+
+func TestCheckNameUnique(t *testing.T) {
+	deletedAt := types.Timestamp(1)
+	existing := []*Collection{
+		{ID: types.NewUniqueID(), Name: "taken", TenantID: "tenant", DatabaseName: "database"},
+		{ID: types.NewUniqueID(), Name: "other_db", TenantID: "tenant", DatabaseName: "other"},
+		{ID: types.NewUniqueID(), Name: "deleted", TenantID: "tenant", DatabaseName: "database", DeletedAt: &deletedAt},
+		nil,
+	}
+
+	// Test case 1: a fresh name, or one only used elsewhere, is unique
+	assert.NoError(t, CheckNameUnique(existing, "fresh", "tenant", "database", types.NilUniqueID()))
+	assert.NoError(t, CheckNameUnique(existing, "other_db", "tenant", "database", types.NilUniqueID()))
+	assert.NoError(t, CheckNameUnique(existing, "taken", "other", "database", types.NilUniqueID()))
+	assert.NoError(t, CheckNameUnique(existing, "deleted", "tenant", "database", types.NilUniqueID()))
+
+	// Test case 2: a conflict, including after normalization
+	for _, name := range []string{"taken", "  taken "} {
+		err := CheckNameUnique(existing, name, "tenant", "database", types.NewUniqueID())
+		assert.ErrorIs(t, err, common.ErrNameConflict)
+		var conflictErr *NameConflictError
+		assert.True(t, errors.As(err, &conflictErr))
+		assert.Equal(t, "taken", conflictErr.Name)
+		assert.Equal(t, existing[0].ID, conflictErr.ExistingID)
+	}
+
+	// Test case 3: renaming a collection to its own name is not a conflict
+	assert.NoError(t, CheckNameUnique(existing, " taken", "tenant", "database", existing[0].ID))
+}
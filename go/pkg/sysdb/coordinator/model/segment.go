@@ -1,23 +1,44 @@
 package model
 
 import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/chroma-core/chroma/go/pkg/common"
 	"github.com/chroma-core/chroma/go/pkg/types"
 )
 
 type Segment struct {
 	ID           types.UniqueID
 	Type         string
-	Scope        string
+	Scope        SegmentScope
 	CollectionID types.UniqueID
 	Metadata     *SegmentMetadata[SegmentMetadataValueType]
 	Ts           types.Timestamp
 	FilePaths    map[string][]string
 }
 
+// Validate reports every problem with the segment as a single
+// *ValidationError.
+func (s *Segment) Validate() error {
+	var violations []error
+	if s.ID == types.NilUniqueID() {
+		violations = append(violations, common.ErrMissingSegmentID)
+	}
+	if s.CollectionID == types.NilUniqueID() {
+		violations = append(violations, common.ErrMissingCollectionID)
+	}
+	if !s.Scope.IsValid() {
+		violations = append(violations, &UnknownSegmentScopeError{Scope: s.Scope})
+	}
+	return newValidationError(violations)
+}
+
 type CreateSegment struct {
 	ID           types.UniqueID
 	Type         string
-	Scope        string
+	Scope        SegmentScope
 	CollectionID types.UniqueID
 	Metadata     *SegmentMetadata[SegmentMetadataValueType]
 	Ts           types.Timestamp
@@ -40,8 +61,86 @@ type GetSegments struct {
 }
 
 type FlushSegmentCompaction struct {
-	ID        types.UniqueID
-	FilePaths map[string][]string
+	ID                 types.UniqueID
+	FilePaths          map[string][]string
+	LastCompactionTime int64
+	RecordsCompacted   uint64
+}
+
+// Validate checks the segment payload of a flush. FilePaths maps a file type
+// to its files; every declared file type must list at least one file, but a
+// segment with no file types is a valid no-op.
+func (f *FlushSegmentCompaction) Validate() error {
+	var violations []error
+	if f.ID == types.NilUniqueID() {
+		violations = append(violations, common.ErrMissingSegmentID)
+	}
+	if f.LastCompactionTime < 0 {
+		violations = append(violations, &InvalidFlushError{Field: "last_compaction_time", Reason: fmt.Sprintf("segment %s has negative time %d", f.ID, f.LastCompactionTime)})
+	}
+	fileTypes := make([]string, 0, len(f.FilePaths))
+	for fileType := range f.FilePaths {
+		fileTypes = append(fileTypes, fileType)
+	}
+	sort.Strings(fileTypes)
+	for _, fileType := range fileTypes {
+		if len(f.FilePaths[fileType]) == 0 {
+			violations = append(violations, &InvalidFlushError{Field: "file_paths", Reason: fmt.Sprintf("segment %s declares file type %q without files", f.ID, fileType)})
+		}
+	}
+	return newValidationError(violations)
+}
+
+// MergeSegmentCompactions combines partial flushes of the same segment into
+// one per segment, ordered by segment ID. File path maps are unioned; a file
+// type listed by several inputs must list the same files in each. Record
+// counts are summed and the latest compaction time is kept. The inputs are not
+// modified.
+func MergeSegmentCompactions(segs []*FlushSegmentCompaction) ([]*FlushSegmentCompaction, error) {
+	merged := make(map[types.UniqueID]*FlushSegmentCompaction)
+	for _, seg := range segs {
+		if seg == nil {
+			continue
+		}
+		target, ok := merged[seg.ID]
+		if !ok {
+			target = &FlushSegmentCompaction{ID: seg.ID, FilePaths: make(map[string][]string)}
+			merged[seg.ID] = target
+		}
+		for fileType, paths := range seg.FilePaths {
+			if existing, ok := target.FilePaths[fileType]; ok {
+				if !equalFilePaths(existing, paths) {
+					return nil, &SegmentCompactionConflictError{SegmentID: seg.ID, FileType: fileType}
+				}
+				continue
+			}
+			target.FilePaths[fileType] = append([]string(nil), paths...)
+		}
+		if seg.LastCompactionTime > target.LastCompactionTime {
+			target.LastCompactionTime = seg.LastCompactionTime
+		}
+		target.RecordsCompacted += seg.RecordsCompacted
+	}
+	result := make([]*FlushSegmentCompaction, 0, len(merged))
+	for _, seg := range merged {
+		result = append(result, seg)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return bytes.Compare(result[i].ID[:], result[j].ID[:]) < 0
+	})
+	return result, nil
+}
+
+func equalFilePaths(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
 }
 
 func FilterSegments(segment *Segment, segmentID types.UniqueID, segmentType *string, scope *string, topic *string, collectionID types.UniqueID) bool {
@@ -52,7 +151,7 @@ func FilterSegments(segment *Segment, segmentID types.UniqueID, segmentType *str
 		return false
 	}
 
-	if scope != nil && segment.Scope != *scope {
+	if scope != nil && segment.Scope != SegmentScope(*scope) {
 		return false
 	}
 
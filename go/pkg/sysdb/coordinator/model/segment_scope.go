@@ -0,0 +1,25 @@
+package model
+
+// SegmentScope is the part of a collection a segment stores. The values match
+// the names of coordinatorpb.SegmentScope.
+type SegmentScope string
+
+const (
+	SegmentScopeVector   SegmentScope = "VECTOR"
+	SegmentScopeMetadata SegmentScope = "METADATA"
+	SegmentScopeRecord   SegmentScope = "RECORD"
+	SegmentScopeSqlite   SegmentScope = "SQLITE"
+)
+
+func (s SegmentScope) String() string {
+	return string(s)
+}
+
+func (s SegmentScope) IsValid() bool {
+	switch s {
+	case SegmentScopeVector, SegmentScopeMetadata, SegmentScopeRecord, SegmentScopeSqlite:
+		return true
+	default:
+		return false
+	}
+}
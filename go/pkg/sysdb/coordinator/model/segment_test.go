@@ -0,0 +1,121 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/chroma-core/chroma/go/pkg/common"
+	"github.com/chroma-core/chroma/go/pkg/proto/coordinatorpb"
+	"github.com/chroma-core/chroma/go/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSegmentScopeString(t *testing.T) {
+	// The scope strings must match the proto enum names.
+	tests := []struct {
+		scope SegmentScope
+		proto coordinatorpb.SegmentScope
+	}{
+		{SegmentScopeVector, coordinatorpb.SegmentScope_VECTOR},
+		{SegmentScopeMetadata, coordinatorpb.SegmentScope_METADATA},
+		{SegmentScopeRecord, coordinatorpb.SegmentScope_RECORD},
+		{SegmentScopeSqlite, coordinatorpb.SegmentScope_SQLITE},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.proto.String(), tt.scope.String())
+		assert.True(t, tt.scope.IsValid())
+	}
+	for _, name := range coordinatorpb.SegmentScope_name {
+		assert.True(t, SegmentScope(name).IsValid(), name)
+	}
+	assert.False(t, SegmentScope("").IsValid())
+	assert.False(t, SegmentScope("vector").IsValid())
+}
+
+func TestSegmentValidate(t *testing.T) {
+	// Test case 1: valid segment
+	segment := &Segment{ID: types.NewUniqueID(), CollectionID: types.NewUniqueID(), Scope: SegmentScopeVector}
+	assert.NoError(t, segment.Validate())
+
+	// Test case 2: unknown scope
+	segment.Scope = "UNKNOWN"
+	err := segment.Validate()
+	var scopeErr *UnknownSegmentScopeError
+	assert.ErrorAs(t, err, &scopeErr)
+	assert.Equal(t, SegmentScope("UNKNOWN"), scopeErr.Scope)
+	assert.ErrorIs(t, err, common.ErrUnknownSegmentScope)
+
+	// Test case 3: nil ids
+	segment = &Segment{Scope: SegmentScopeRecord}
+	err = segment.Validate()
+	assert.ErrorIs(t, err, common.ErrMissingSegmentID)
+	assert.ErrorIs(t, err, common.ErrMissingCollectionID)
+}
+
+func TestFlushSegmentCompactionValidate(t *testing.T) {
+	// Test case 1: valid segment
+	segment := &FlushSegmentCompaction{
+		ID:        types.NewUniqueID(),
+		FilePaths: map[string][]string{"hnsw_index": {"a", "b"}, "id_to_data": {"c"}},
+	}
+	assert.NoError(t, segment.Validate())
+
+	// Test case 2: no declared file types is a no-op
+	assert.NoError(t, (&FlushSegmentCompaction{ID: types.NewUniqueID()}).Validate())
+
+	// Test case 3: nil segment id
+	segment = &FlushSegmentCompaction{FilePaths: map[string][]string{"hnsw_index": {"a"}}}
+	assert.ErrorIs(t, segment.Validate(), common.ErrMissingSegmentID)
+
+	// Test case 4: declared file type without files
+	segment = &FlushSegmentCompaction{
+		ID:        types.NewUniqueID(),
+		FilePaths: map[string][]string{"hnsw_index": {"a"}, "id_to_data": {}},
+	}
+	err := segment.Validate()
+	var invalid *InvalidFlushError
+	assert.ErrorAs(t, err, &invalid)
+	assert.Equal(t, "file_paths", invalid.Field)
+	assert.Contains(t, invalid.Reason, "id_to_data")
+
+	// Test case 5: flush compactions validate their segments
+	flush := &FlushCollectionCompaction{FlushSegmentCompactions: []*FlushSegmentCompaction{segment}}
+	assert.ErrorIs(t, flush.Validate(0, 0), common.ErrInvalidFlush)
+}
+
+func TestMergeSegmentCompactions(t *testing.T) {
+	first := types.MustParse("00000000-0000-0000-0000-000000000001")
+	second := types.MustParse("00000000-0000-0000-0000-000000000002")
+	third := types.MustParse("00000000-0000-0000-0000-000000000003")
+
+	// Test case 1: clean merge of disjoint keys, ordered by segment id
+	merged, err := MergeSegmentCompactions([]*FlushSegmentCompaction{
+		{ID: third, FilePaths: map[string][]string{"hnsw_index": {"c"}}},
+		{ID: first, FilePaths: map[string][]string{"hnsw_index": {"a"}}, RecordsCompacted: 10, LastCompactionTime: 5},
+		nil,
+		{ID: second},
+		{ID: first, FilePaths: map[string][]string{"id_to_data": {"b"}, "hnsw_index": {"a"}}, RecordsCompacted: 5, LastCompactionTime: 3},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, merged, 3)
+	assert.Equal(t, []types.UniqueID{first, second, third}, []types.UniqueID{merged[0].ID, merged[1].ID, merged[2].ID})
+	assert.Equal(t, map[string][]string{"hnsw_index": {"a"}, "id_to_data": {"b"}}, merged[0].FilePaths)
+	assert.Equal(t, uint64(15), merged[0].RecordsCompacted)
+	assert.Equal(t, int64(5), merged[0].LastCompactionTime)
+	assert.Empty(t, merged[1].FilePaths)
+
+	// Test case 2: conflicting files for the same key
+	_, err = MergeSegmentCompactions([]*FlushSegmentCompaction{
+		{ID: first, FilePaths: map[string][]string{"hnsw_index": {"a"}}},
+		{ID: first, FilePaths: map[string][]string{"hnsw_index": {"b"}}},
+	})
+	var conflict *SegmentCompactionConflictError
+	assert.ErrorAs(t, err, &conflict)
+	assert.Equal(t, first, conflict.SegmentID)
+	assert.Equal(t, "hnsw_index", conflict.FileType)
+	assert.ErrorIs(t, err, common.ErrInvalidFlush)
+
+	// Test case 3: no input
+	merged, err = MergeSegmentCompactions(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, merged)
+}
@@ -1,9 +1,45 @@
 package model
 
-import "github.com/chroma-core/chroma/go/pkg/types"
+import (
+	"github.com/chroma-core/chroma/go/pkg/common"
+	"github.com/chroma-core/chroma/go/pkg/types"
+)
 
+// Tenant is identified by its Name. LastCompactionTime is the most recent
+// compaction of any collection in the tenant; 0 means never.
 type Tenant struct {
-	Name string
+	Name               string
+	Ts                 types.Timestamp
+	LastCompactionTime int64
+}
+
+func (t *Tenant) Validate() error {
+	if t.Name == "" {
+		return common.ErrTenantIDEmpty
+	}
+	return nil
+}
+
+// FilterTenant reports whether tenant has the given ID. A nil id matches
+// every tenant. A nil tenant never matches.
+func FilterTenant(tenant *Tenant, id *string) bool {
+	if tenant == nil {
+		return false
+	}
+	return id == nil || *id == tenant.Name
+}
+
+// SetTenantLastCompactionTime records ts as the tenant's last compaction
+// time. Negative times are rejected and leave the tenant unchanged.
+func SetTenantLastCompactionTime(tenant *Tenant, ts int64) error {
+	if tenant == nil {
+		return common.ErrTenantNotFound
+	}
+	if ts < 0 {
+		return &InvalidCompactionTimeError{TenantID: tenant.Name, Time: ts}
+	}
+	tenant.LastCompactionTime = ts
+	return nil
 }
 
 type CreateTenant struct {
@@ -20,3 +56,36 @@ type TenantLastCompactionTime struct {
 	ID string
 	Ts types.Timestamp
 }
+
+// TenantLimits are guardrails on a tenant's total resource use. A zero limit
+// means unlimited.
+type TenantLimits struct {
+	MaxDatabases                  int32
+	MaxCollectionsTotal           int32
+	MaxMetadataBytesPerCollection int32
+}
+
+// TenantUsage is the resource use a tenant would have after the operation
+// being checked.
+type TenantUsage struct {
+	Databases                 int32
+	CollectionsTotal          int32
+	LargestCollectionMetadata int32
+}
+
+// CheckTenantLimits reports every limit that usage exceeds as a single
+// *ValidationError. Each violation has its own error type so callers can tell
+// which limit was hit.
+func CheckTenantLimits(limits TenantLimits, usage TenantUsage) error {
+	var violations []error
+	if limits.MaxDatabases > 0 && usage.Databases > limits.MaxDatabases {
+		violations = append(violations, &DatabaseLimitExceededError{Limit: limits.MaxDatabases, Usage: usage.Databases})
+	}
+	if limits.MaxCollectionsTotal > 0 && usage.CollectionsTotal > limits.MaxCollectionsTotal {
+		violations = append(violations, &CollectionLimitExceededError{Limit: limits.MaxCollectionsTotal, Usage: usage.CollectionsTotal})
+	}
+	if limits.MaxMetadataBytesPerCollection > 0 && usage.LargestCollectionMetadata > limits.MaxMetadataBytesPerCollection {
+		violations = append(violations, &MetadataBytesLimitExceededError{Limit: limits.MaxMetadataBytesPerCollection, Usage: usage.LargestCollectionMetadata})
+	}
+	return newValidationError(violations)
+}
@@ -0,0 +1,96 @@
+package model
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/chroma-core/chroma/go/pkg/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckTenantLimits(t *testing.T) {
+	limits := TenantLimits{MaxDatabases: 2, MaxCollectionsTotal: 100, MaxMetadataBytesPerCollection: 1024}
+	within := TenantUsage{Databases: 2, CollectionsTotal: 100, LargestCollectionMetadata: 1024}
+
+	// Test case 1: usage at the limits is allowed
+	assert.NoError(t, CheckTenantLimits(limits, within))
+
+	// Test case 2: zero limits are unlimited
+	assert.NoError(t, CheckTenantLimits(TenantLimits{}, TenantUsage{Databases: 1000, CollectionsTotal: 50000, LargestCollectionMetadata: 1 << 20}))
+
+	// Test case 3: each limit independently
+	usage := within
+	usage.Databases = 3
+	err := CheckTenantLimits(limits, usage)
+	var databases *DatabaseLimitExceededError
+	assert.ErrorAs(t, err, &databases)
+	assert.Equal(t, int32(2), databases.Limit)
+	assert.Equal(t, int32(3), databases.Usage)
+	assert.ErrorIs(t, err, common.ErrQuotaExceeded)
+
+	usage = within
+	usage.CollectionsTotal = 101
+	err = CheckTenantLimits(limits, usage)
+	var collections *CollectionLimitExceededError
+	assert.ErrorAs(t, err, &collections)
+	assert.False(t, errors.As(err, &databases))
+
+	usage = within
+	usage.LargestCollectionMetadata = 1025
+	err = CheckTenantLimits(limits, usage)
+	var metadataBytes *MetadataBytesLimitExceededError
+	assert.ErrorAs(t, err, &metadataBytes)
+	assert.False(t, errors.As(err, &collections))
+
+	// Test case 4: every exceeded limit is reported
+	err = CheckTenantLimits(limits, TenantUsage{Databases: 3, CollectionsTotal: 101, LargestCollectionMetadata: 1025})
+	var validationErr *ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Len(t, validationErr.Violations, 3)
+	assert.ErrorAs(t, err, &databases)
+	assert.ErrorAs(t, err, &collections)
+	assert.ErrorAs(t, err, &metadataBytes)
+}
+
+func TestTenantValidate(t *testing.T) {
+	assert.NoError(t, (&Tenant{Name: "tenant"}).Validate())
+	assert.ErrorIs(t, (&Tenant{}).Validate(), common.ErrTenantIDEmpty)
+}
+
+func TestFilterTenant(t *testing.T) {
+	tenant := &Tenant{Name: "tenant"}
+	id := "tenant"
+	other := "other"
+
+	// Test case 1: a nil id matches every tenant
+	assert.True(t, FilterTenant(tenant, nil))
+	assert.True(t, FilterTenant(tenant, &id))
+
+	// Test case 2: mismatched id
+	assert.False(t, FilterTenant(tenant, &other))
+
+	// Test case 3: nil tenant never matches
+	assert.False(t, FilterTenant(nil, nil))
+}
+
+func TestSetTenantLastCompactionTime(t *testing.T) {
+	tenant := &Tenant{Name: "tenant"}
+
+	// Test case 1: valid times are recorded
+	assert.NoError(t, SetTenantLastCompactionTime(tenant, 100))
+	assert.Equal(t, int64(100), tenant.LastCompactionTime)
+	assert.NoError(t, SetTenantLastCompactionTime(tenant, 0))
+	assert.Equal(t, int64(0), tenant.LastCompactionTime)
+
+	// Test case 2: negative times are rejected without changing the tenant
+	tenant.LastCompactionTime = 100
+	err := SetTenantLastCompactionTime(tenant, -1)
+	assert.ErrorIs(t, err, common.ErrInvalidCompactionTime)
+	var timeErr *InvalidCompactionTimeError
+	assert.True(t, errors.As(err, &timeErr))
+	assert.Equal(t, int64(-1), timeErr.Time)
+	assert.Equal(t, int64(100), tenant.LastCompactionTime)
+
+	// Test case 3: nil tenant
+	assert.ErrorIs(t, SetTenantLastCompactionTime(nil, 1), common.ErrTenantNotFound)
+}
@@ -101,7 +101,7 @@ func convertSegmentToModel(segmentAndMetadataList []*dbmodel.SegmentAndMetadata)
 		segment := &model.Segment{
 			ID:    types.MustParse(segmentAndMetadata.Segment.ID),
 			Type:  segmentAndMetadata.Segment.Type,
-			Scope: segmentAndMetadata.Segment.Scope,
+			Scope: convertSegmentScopeToModel(segmentAndMetadata.Segment.Scope),
 			Ts:    segmentAndMetadata.Segment.Ts,
 		}
 		if segmentAndMetadata.Segment.CollectionID != nil {
@@ -117,6 +117,16 @@ func convertSegmentToModel(segmentAndMetadataList []*dbmodel.SegmentAndMetadata)
 	return segments
 }
 
+// convertSegmentScopeToModel keeps a scope read from storage even when it is
+// not a known SegmentScope, so existing rows stay readable, but logs it.
+func convertSegmentScopeToModel(scope string) model.SegmentScope {
+	segmentScope := model.SegmentScope(scope)
+	if !segmentScope.IsValid() {
+		log.Warn("unknown segment scope in storage", zap.String("scope", scope))
+	}
+	return segmentScope
+}
+
 func convertSegmentMetadataToModel(segmentMetadataList []*dbmodel.SegmentMetadata) *model.SegmentMetadata[model.SegmentMetadataValueType] {
 	if segmentMetadataList == nil {
 		return nil
@@ -91,7 +91,7 @@ func TestConvertSegmentToModel(t *testing.T) {
 	assert.Len(t, modelSegments, 1)
 	assert.Equal(t, segmentID, modelSegments[0].ID)
 	assert.Equal(t, "segment_type", modelSegments[0].Type)
-	assert.Equal(t, "segment_scope", modelSegments[0].Scope)
+	assert.Equal(t, model.SegmentScope("segment_scope"), modelSegments[0].Scope)
 	assert.Equal(t, types.MustParse(collectionID), modelSegments[0].CollectionID)
 	assert.Nil(t, modelSegments[0].Metadata)
 }
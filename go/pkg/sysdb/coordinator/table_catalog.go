@@ -409,7 +409,7 @@ func (tc *Catalog) CreateSegment(ctx context.Context, createSegment *model.Creat
 			ID:           createSegment.ID.String(),
 			CollectionID: &collectionString,
 			Type:         createSegment.Type,
-			Scope:        createSegment.Scope,
+			Scope:        createSegment.Scope.String(),
 			Ts:           ts,
 		}
 		err := tc.metaDomain.SegmentDb(txCtx).Insert(dbSegment)
@@ -462,7 +462,7 @@ func (tc *Catalog) GetSegments(ctx context.Context, segmentID types.UniqueID, se
 		segment := &model.Segment{
 			ID:        types.MustParse(segmentAndMetadata.Segment.ID),
 			Type:      segmentAndMetadata.Segment.Type,
-			Scope:     segmentAndMetadata.Segment.Scope,
+			Scope:     convertSegmentScopeToModel(segmentAndMetadata.Segment.Scope),
 			Ts:        segmentAndMetadata.Segment.Ts,
 			FilePaths: segmentAndMetadata.Segment.FilePaths,
 		}
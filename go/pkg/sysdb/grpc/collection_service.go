@@ -154,6 +154,8 @@ func (s *Server) UpdateCollection(ctx context.Context, req *coordinatorpb.Update
 		return res, grpcutils.BuildInternalGrpcError(err.Error())
 	}
 
+	// The request carries no tenant or database; UpdateCollection.Validate
+	// takes the scope from the existing collection.
 	updateCollection := &model.UpdateCollection{
 		ID:        parsedCollectionID,
 		Name:      req.Name,
@@ -10,92 +10,21 @@ import (
 )
 
 func convertCollectionMetadataToModel(collectionMetadata *coordinatorpb.UpdateMetadata) (*model.CollectionMetadata[model.CollectionMetadataValueType], error) {
-	if collectionMetadata == nil {
-		return nil, nil
-	}
-
-	metadata := model.NewCollectionMetadata[model.CollectionMetadataValueType]()
-	for key, value := range collectionMetadata.Metadata {
-		switch v := (value.Value).(type) {
-		case *coordinatorpb.UpdateMetadataValue_BoolValue:
-			metadata.Add(key, &model.CollectionMetadataValueBoolType{Value: v.BoolValue})
-		case *coordinatorpb.UpdateMetadataValue_StringValue:
-			metadata.Add(key, &model.CollectionMetadataValueStringType{Value: v.StringValue})
-		case *coordinatorpb.UpdateMetadataValue_IntValue:
-			metadata.Add(key, &model.CollectionMetadataValueInt64Type{Value: v.IntValue})
-		case *coordinatorpb.UpdateMetadataValue_FloatValue:
-			metadata.Add(key, &model.CollectionMetadataValueFloat64Type{Value: v.FloatValue})
-		default:
-			log.Error("collection metadata value type not supported", zap.Any("metadata value", value))
-			return nil, common.ErrUnknownCollectionMetadataType
-		}
+	metadata, err := model.CollectionMetadataFromProto(collectionMetadata)
+	if err != nil {
+		log.Error("collection metadata value type not supported", zap.Error(err))
+		return nil, err
 	}
 	log.Debug("collection metadata in model", zap.Any("metadata", metadata))
 	return metadata, nil
 }
 
 func convertCollectionToProto(collection *model.Collection) *coordinatorpb.Collection {
-	if collection == nil {
-		return nil
-	}
-
-	collectionpb := &coordinatorpb.Collection{
-		Id:                   collection.ID.String(),
-		Name:                 collection.Name,
-		ConfigurationJsonStr: collection.ConfigurationJsonStr,
-		Dimension:            collection.Dimension,
-		Tenant:               collection.TenantID,
-		Database:             collection.DatabaseName,
-		LogPosition:          collection.LogPosition,
-		Version:              collection.Version,
-	}
-	if collection.Metadata == nil {
-		return collectionpb
-	}
-
-	metadatapb := convertCollectionMetadataToProto(collection.Metadata)
-	collectionpb.Metadata = metadatapb
-	return collectionpb
+	return collection.ToProto()
 }
 
 func convertCollectionMetadataToProto(collectionMetadata *model.CollectionMetadata[model.CollectionMetadataValueType]) *coordinatorpb.UpdateMetadata {
-	if collectionMetadata == nil {
-		return nil
-	}
-	metadatapb := &coordinatorpb.UpdateMetadata{
-		Metadata: make(map[string]*coordinatorpb.UpdateMetadataValue),
-	}
-	for key, value := range collectionMetadata.Metadata {
-		switch v := (value).(type) {
-		case *model.CollectionMetadataValueBoolType:
-			metadatapb.Metadata[key] = &coordinatorpb.UpdateMetadataValue{
-				Value: &coordinatorpb.UpdateMetadataValue_BoolValue{
-					BoolValue: v.Value,
-				},
-			}
-		case *model.CollectionMetadataValueStringType:
-			metadatapb.Metadata[key] = &coordinatorpb.UpdateMetadataValue{
-				Value: &coordinatorpb.UpdateMetadataValue_StringValue{
-					StringValue: v.Value,
-				},
-			}
-		case *model.CollectionMetadataValueInt64Type:
-			metadatapb.Metadata[key] = &coordinatorpb.UpdateMetadataValue{
-				Value: &coordinatorpb.UpdateMetadataValue_IntValue{
-					IntValue: v.Value,
-				},
-			}
-		case *model.CollectionMetadataValueFloat64Type:
-			metadatapb.Metadata[key] = &coordinatorpb.UpdateMetadataValue{
-				Value: &coordinatorpb.UpdateMetadataValue_FloatValue{
-					FloatValue: v.Value,
-				},
-			}
-		default:
-			log.Error("collection metadata value type not supported", zap.Any("metadata value", value))
-		}
-	}
-	return metadatapb
+	return model.CollectionMetadataToProto(collectionMetadata)
 }
 
 func convertToCreateCollectionModel(req *coordinatorpb.CreateCollectionRequest) (*model.CreateCollection, error) {
@@ -156,7 +85,7 @@ func convertSegmentToProto(segment *model.Segment) *coordinatorpb.Segment {
 	if segment == nil {
 		return nil
 	}
-	scope := coordinatorpb.SegmentScope_value[segment.Scope]
+	scope := coordinatorpb.SegmentScope_value[segment.Scope.String()]
 	segmentSceope := coordinatorpb.SegmentScope(scope)
 	filePaths := make(map[string]*coordinatorpb.FilePaths)
 	for t, paths := range segment.FilePaths {
@@ -233,10 +162,16 @@ func convertSegmentToModel(segmentpb *coordinatorpb.Segment) (*model.CreateSegme
 		return nil, err
 	}
 
+	scope := model.SegmentScope(segmentpb.Scope.String())
+	if !scope.IsValid() {
+		log.Error("unknown segment scope", zap.String("scope", string(scope)))
+		return nil, &model.UnknownSegmentScopeError{Scope: scope}
+	}
+
 	return &model.CreateSegment{
 		ID:           segmentID,
 		Type:         segmentpb.Type,
-		Scope:        segmentpb.Scope.String(),
+		Scope:        scope,
 		CollectionID: collectionID,
 		Metadata:     metadata,
 	}, nil
@@ -3,6 +3,7 @@ package grpc
 import (
 	"testing"
 
+	"github.com/chroma-core/chroma/go/pkg/common"
 	"github.com/chroma-core/chroma/go/pkg/proto/coordinatorpb"
 	"github.com/chroma-core/chroma/go/pkg/sysdb/coordinator/model"
 	"github.com/chroma-core/chroma/go/pkg/types"
@@ -42,6 +43,14 @@ func TestConvertCollectionMetadataToModel(t *testing.T) {
 	assert.Equal(t, "value1", metadata.Get("key1").(*model.CollectionMetadataValueStringType).Value)
 	assert.Equal(t, int64(123), metadata.Get("key2").(*model.CollectionMetadataValueInt64Type).Value)
 	assert.Equal(t, 3.14, metadata.Get("key3").(*model.CollectionMetadataValueFloat64Type).Value)
+
+	// Test case 3: unsupported value type, matching the model conversion
+	collectionMetadata.Metadata["key4"] = &coordinatorpb.UpdateMetadataValue{}
+	metadata, err = convertCollectionMetadataToModel(collectionMetadata)
+	assert.Nil(t, metadata)
+	assert.ErrorIs(t, err, common.ErrUnknownCollectionMetadataType)
+	_, modelErr := model.CollectionMetadataFromProto(collectionMetadata)
+	assert.Equal(t, modelErr, err)
 }
 
 func TestConvertCollectionToProto(t *testing.T) {
@@ -196,3 +205,29 @@ func TestConvertSegmentToProto(t *testing.T) {
 	assert.Equal(t, uuid.Nil.String(), segmentpb.Collection)
 	assert.Nil(t, segmentpb.Metadata)
 }
+
+func TestConvertSegmentToModel(t *testing.T) {
+	// Test case 1: known scope
+	segmentpb := &coordinatorpb.Segment{
+		Id:         types.NewUniqueID().String(),
+		Type:       "test_type",
+		Scope:      coordinatorpb.SegmentScope_VECTOR,
+		Collection: types.NewUniqueID().String(),
+	}
+	segment, err := convertSegmentToModel(segmentpb)
+	assert.NoError(t, err)
+	assert.Equal(t, model.SegmentScopeVector, segment.Scope)
+
+	// Test case 2: SQLITE scope
+	segmentpb.Scope = coordinatorpb.SegmentScope_SQLITE
+	segment, err = convertSegmentToModel(segmentpb)
+	assert.NoError(t, err)
+	assert.Equal(t, model.SegmentScopeSqlite, segment.Scope)
+
+	// Test case 3: unknown scope is rejected
+	segmentpb.Scope = coordinatorpb.SegmentScope(99)
+	segment, err = convertSegmentToModel(segmentpb)
+	assert.Nil(t, segment)
+	var scopeErr *model.UnknownSegmentScopeError
+	assert.ErrorAs(t, err, &scopeErr)
+}
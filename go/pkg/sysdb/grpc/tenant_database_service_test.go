@@ -12,6 +12,7 @@ import (
 	"github.com/chroma-core/chroma/go/pkg/sysdb/coordinator/model"
 	"github.com/chroma-core/chroma/go/pkg/sysdb/metastore/db/dao"
 	"github.com/chroma-core/chroma/go/pkg/sysdb/metastore/db/dbcore"
+	"github.com/chroma-core/chroma/go/pkg/types"
 	"github.com/pingcap/log"
 	"github.com/stretchr/testify/suite"
 	"google.golang.org/genproto/googleapis/rpc/code"
@@ -65,8 +66,8 @@ func (suite *TenantDatabaseServiceTestSuite) TestServer_TenantLastCompactionTime
 	// create tenant
 	_, err = suite.catalog.CreateTenant(context.Background(), &model.CreateTenant{
 		Name: tenantId,
-		Ts:   time.Now().Unix(),
-	}, time.Now().Unix())
+		Ts:   types.TimestampFromTime(time.Now()),
+	}, types.TimestampFromTime(time.Now()))
 	if err != nil {
 		return
 	}
@@ -1,15 +1,31 @@
 package types
 
 import (
+	"fmt"
 	"math"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 )
 
-type Timestamp = int64
+// Timestamp is a point in time in seconds since the Unix epoch.
+type Timestamp int64
 
 const MaxTimestamp = Timestamp(math.MaxInt64)
 
+func TimestampFromTime(tm time.Time) Timestamp {
+	return Timestamp(tm.Unix())
+}
+
+func (t Timestamp) ToTime() time.Time {
+	return time.Unix(int64(t), 0)
+}
+
+func (t Timestamp) IsZero() bool {
+	return t == 0
+}
+
 type UniqueID uuid.UUID
 
 func NewUniqueID() UniqueID {
@@ -33,6 +49,34 @@ func NilUniqueID() UniqueID {
 	return UniqueID(uuid.Nil)
 }
 
+func (id UniqueID) IsNil() bool {
+	return id == NilUniqueID()
+}
+
+// InvalidUniqueIDError is returned by ParseUniqueID for input that is not a
+// well formed UUID.
+type InvalidUniqueIDError struct {
+	Input  string
+	Reason string
+}
+
+func (e *InvalidUniqueIDError) Error() string {
+	return fmt.Sprintf("invalid unique id %q: %s", e.Input, e.Reason)
+}
+
+// ParseUniqueID parses s as a UUID. Unlike Parse it rejects surrounding
+// whitespace and reports failures as *InvalidUniqueIDError.
+func ParseUniqueID(s string) (UniqueID, error) {
+	if strings.TrimSpace(s) != s {
+		return NilUniqueID(), &InvalidUniqueIDError{Input: s, Reason: "contains leading or trailing whitespace"}
+	}
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return NilUniqueID(), &InvalidUniqueIDError{Input: s, Reason: err.Error()}
+	}
+	return UniqueID(id), nil
+}
+
 func ToUniqueID(idString *string) (UniqueID, error) {
 	if idString != nil {
 		id, err := Parse(*idString)
@@ -56,3 +100,47 @@ func FromUniqueID(id UniqueID) *string {
 	}
 	return idStringPointer
 }
+
+// UniqueIDSet is a set of UniqueIDs. The zero value is an empty set ready to
+// use, and the read methods are safe on a nil set.
+type UniqueIDSet struct {
+	ids map[UniqueID]struct{}
+}
+
+func NewUniqueIDSet(ids ...UniqueID) *UniqueIDSet {
+	s := &UniqueIDSet{ids: make(map[UniqueID]struct{}, len(ids))}
+	for _, id := range ids {
+		s.Add(id)
+	}
+	return s
+}
+
+// Add inserts id; adding an id already in the set has no effect.
+func (s *UniqueIDSet) Add(id UniqueID) {
+	if s.ids == nil {
+		s.ids = make(map[UniqueID]struct{})
+	}
+	s.ids[id] = struct{}{}
+}
+
+func (s *UniqueIDSet) Contains(id UniqueID) bool {
+	if s == nil {
+		return false
+	}
+	_, ok := s.ids[id]
+	return ok
+}
+
+func (s *UniqueIDSet) Remove(id UniqueID) {
+	if s == nil {
+		return
+	}
+	delete(s.ids, id)
+}
+
+func (s *UniqueIDSet) Len() int {
+	if s == nil {
+		return 0
+	}
+	return len(s.ids)
+}
@@ -0,0 +1,105 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimestampTimeConversion(t *testing.T) {
+	// Test case 1: round trip from time.Time at second precision
+	tm := time.Date(2024, 5, 17, 12, 30, 45, 0, time.UTC)
+	ts := TimestampFromTime(tm)
+	assert.Equal(t, Timestamp(tm.Unix()), ts)
+	assert.True(t, tm.Equal(ts.ToTime()))
+
+	// Test case 2: sub-second precision is truncated
+	assert.Equal(t, ts, TimestampFromTime(tm.Add(999*time.Millisecond)))
+
+	// Test case 3: round trip from Timestamp
+	assert.Equal(t, Timestamp(1700000000), TimestampFromTime(Timestamp(1700000000).ToTime()))
+}
+
+func TestTimestampIsZero(t *testing.T) {
+	var zero Timestamp
+	assert.True(t, zero.IsZero())
+	assert.True(t, zero.ToTime().Equal(time.Unix(0, 0)))
+	assert.False(t, Timestamp(1).IsZero())
+	assert.False(t, MaxTimestamp.IsZero())
+}
+
+func TestParseUniqueID(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected UniqueID
+		valid    bool
+	}{
+		{name: "valid", input: "6ba7b810-9dad-11d1-80b4-00c04fd430c8", expected: MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8"), valid: true},
+		{name: "uppercase", input: "6BA7B810-9DAD-11D1-80B4-00C04FD430C8", expected: MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8"), valid: true},
+		{name: "nil uuid", input: "00000000-0000-0000-0000-000000000000", expected: NilUniqueID(), valid: true},
+		{name: "empty", input: ""},
+		{name: "malformed", input: "not-a-uuid"},
+		{name: "bad character", input: "6ba7b810-9dad-11d1-80b4-00c04fd430cg"},
+		{name: "truncated", input: "6ba7b810-9dad-11d1-80b4-00c04fd430c"},
+		{name: "leading whitespace", input: " 6ba7b810-9dad-11d1-80b4-00c04fd430c8"},
+		{name: "trailing whitespace", input: "6ba7b810-9dad-11d1-80b4-00c04fd430c8\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := ParseUniqueID(tt.input)
+			if tt.valid {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, id)
+				return
+			}
+			var invalid *InvalidUniqueIDError
+			assert.ErrorAs(t, err, &invalid)
+			assert.Equal(t, tt.input, invalid.Input)
+			assert.True(t, id.IsNil())
+		})
+	}
+}
+
+func TestUniqueIDIsNil(t *testing.T) {
+	assert.True(t, NilUniqueID().IsNil())
+	assert.True(t, UniqueID{}.IsNil())
+	assert.False(t, NewUniqueID().IsNil())
+}
+
+func TestUniqueIDSet(t *testing.T) {
+	a, b, c := NewUniqueID(), NewUniqueID(), NewUniqueID()
+
+	// Test case 1: add, contains and remove
+	set := NewUniqueIDSet(a, b)
+	assert.Equal(t, 2, set.Len())
+	assert.True(t, set.Contains(a))
+	assert.True(t, set.Contains(b))
+	assert.False(t, set.Contains(c))
+	set.Add(c)
+	assert.True(t, set.Contains(c))
+	set.Remove(a)
+	assert.False(t, set.Contains(a))
+	assert.Equal(t, 2, set.Len())
+	set.Remove(a)
+	assert.Equal(t, 2, set.Len())
+
+	// Test case 2: duplicate adds are idempotent
+	set = NewUniqueIDSet(a, a)
+	set.Add(a)
+	assert.Equal(t, 1, set.Len())
+
+	// Test case 3: the zero value is usable
+	var zero UniqueIDSet
+	assert.False(t, zero.Contains(a))
+	zero.Add(a)
+	assert.True(t, zero.Contains(a))
+
+	// Test case 4: nil sets are empty
+	var nilSet *UniqueIDSet
+	assert.False(t, nilSet.Contains(a))
+	assert.Equal(t, 0, nilSet.Len())
+	nilSet.Remove(a)
+	assert.Equal(t, 0, NewUniqueIDSet().Len())
+}